@@ -1,6 +1,7 @@
 package clause
 
 import (
+	"geeorm/dialect"
 	"strings"
 )
 
@@ -18,12 +19,31 @@ const(
 	UPDATE
 	DELETE
 	COUNT
+	ONCONFLICT
+	RETURNING
+	OFFSET
+	GROUPBY
+	HAVING
+	AGGREGATE
 )
 
+// OnConflict INSERT语句中主键或唯一约束冲突时的处理方式
+type OnConflict struct {
+	Columns   []string // 判断冲突的列
+	DoUpdates []string // 冲突时使用插入的值更新的列
+	DoNothing bool     // 冲突时忽略插入的行
+}
+
 // Clause 数据库操作语句，可以包含多种子操作
 type Clause struct {
 	sql map[Type]string
 	sqlVars map[Type][]interface{}
+	dial dialect.Dialect
+}
+
+// New 创建一个使用给定方言为标识符加引号、改写占位符的Clause
+func New(d dialect.Dialect) Clause {
+	return Clause{dial: d}
 }
 
 // Set 用来为Clause构造一个给定操作的子语句
@@ -33,10 +53,16 @@ func (c *Clause) Set(name Type, vars ...interface{}) {
 		c.sql = make(map[Type]string)
 		c.sqlVars = make(map[Type][]interface{})
 	}
-	c.sql[name], c.sqlVars[name] = generators[name](vars...)
+	c.sql[name], c.sqlVars[name] = generators[name](c.dial, vars...)
+}
+
+// Has 判断是否已经设置了给定操作的子语句
+func (c *Clause) Has(name Type) bool {
+	_, ok := c.sql[name]
+	return ok
 }
 
-// Build 用来根据给定的操作顺序构造完整的SQL语句
+// Build 用来根据给定的操作顺序构造完整的SQL语句，参数统一使用 ? 占位符，执行时由会话改写为方言的占位符
 func (c *Clause) Build(orders ...Type) (string, []interface{}) {
 	var sqls []string
 	var vars []interface{}
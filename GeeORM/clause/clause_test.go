@@ -1,6 +1,7 @@
 package clause
 
 import (
+	"geeorm/dialect"
 	"reflect"
 	"testing"
 )
@@ -21,8 +22,133 @@ func testSelect(t *testing.T) {
 	}
 }
 
+func testDialect(t *testing.T, name string, expect map[string]string) {
+	d, _ := dialect.GetDialect(name)
+	clause := New(d)
+	clause.Set(SELECT, "User", []string{"Name", "Age"})
+	clause.Set(WHERE, "Name = ? AND Age > ?", "Tom", 18)
+	clause.Set(LIMIT, 3)
+	if sql, _ := clause.Build(SELECT, WHERE, LIMIT); sql != expect["select"] {
+		t.Fatalf("expect %q, but got %q", expect["select"], sql)
+	}
+
+	clause = New(d)
+	clause.Set(INSERT, "User", []string{"Name", "Age"})
+	clause.Set(VALUES, []interface{}{"Tom", 18}, []interface{}{"Sam", 20})
+	sql, vars := clause.Build(INSERT, VALUES)
+	if sql != expect["insert"] {
+		t.Fatalf("expect %q, but got %q", expect["insert"], sql)
+	}
+	if !reflect.DeepEqual(vars, []interface{}{"Tom", 18, "Sam", 20}) {
+		t.Fatal("failed to build SQLVars")
+	}
+
+	clause = New(d)
+	clause.Set(UPDATE, "User", map[string]interface{}{"Age": 30})
+	clause.Set(WHERE, "Name = ?", "Tom")
+	if sql, _ := clause.Build(UPDATE, WHERE); sql != expect["update"] {
+		t.Fatalf("expect %q, but got %q", expect["update"], sql)
+	}
+
+	clause = New(d)
+	clause.Set(INSERT, "User", []string{"Name", "Age"})
+	clause.Set(VALUES, []interface{}{"Tom", 18})
+	clause.Set(ONCONFLICT, OnConflict{Columns: []string{"Name"}, DoUpdates: []string{"Age"}})
+	if sql, _ := clause.Build(INSERT, VALUES, ONCONFLICT); sql != expect["upsert"] {
+		t.Fatalf("expect %q, but got %q", expect["upsert"], sql)
+	}
+	clause.Set(ONCONFLICT, OnConflict{Columns: []string{"Name"}, DoNothing: true})
+	if sql, _ := clause.Build(INSERT, VALUES, ONCONFLICT); sql != expect["ignore"] {
+		t.Fatalf("expect %q, but got %q", expect["ignore"], sql)
+	}
+	// 未指定冲突列时，不支持的方言返回空语句，由调用方报错而不是退化为普通插入
+	clause.Set(ONCONFLICT, OnConflict{DoNothing: true})
+	if sql, _ := clause.Build(ONCONFLICT); sql != expect["ignoreAny"] {
+		t.Fatalf("expect %q, but got %q", expect["ignoreAny"], sql)
+	}
+}
+
 func TestClause_Build(t *testing.T) {
 	t.Run("select", func(t *testing.T){
 		testSelect(t)
 	})
-}
\ No newline at end of file
+	t.Run("sqlite3", func(t *testing.T) {
+		testDialect(t, "sqlite3", map[string]string{
+			"select": `SELECT "Name","Age" FROM "User" WHERE Name = ? AND Age > ? LIMIT ?`,
+			"insert": `INSERT INTO "User" ("Name","Age") VALUES (?,?),(?,?)`,
+			"update": `UPDATE "User" SET "Age" = ? WHERE Name = ?`,
+			"upsert": `INSERT INTO "User" ("Name","Age") VALUES (?,?) ON CONFLICT ("Name") DO UPDATE SET "Age" = excluded."Age"`,
+			"ignore": `INSERT INTO "User" ("Name","Age") VALUES (?,?) ON CONFLICT ("Name") DO NOTHING`,
+			"ignoreAny": `ON CONFLICT DO NOTHING`,
+		})
+	})
+	t.Run("postgres", func(t *testing.T) {
+		testDialect(t, "postgres", map[string]string{
+			"select": `SELECT "Name","Age" FROM "User" WHERE Name = ? AND Age > ? LIMIT ?`,
+			"insert": `INSERT INTO "User" ("Name","Age") VALUES (?,?),(?,?)`,
+			"update": `UPDATE "User" SET "Age" = ? WHERE Name = ?`,
+			"upsert": `INSERT INTO "User" ("Name","Age") VALUES (?,?) ON CONFLICT ("Name") DO UPDATE SET "Age" = EXCLUDED."Age"`,
+			"ignore": `INSERT INTO "User" ("Name","Age") VALUES (?,?) ON CONFLICT ("Name") DO NOTHING`,
+			"ignoreAny": `ON CONFLICT DO NOTHING`,
+		})
+	})
+	t.Run("mysql", func(t *testing.T) {
+		testDialect(t, "mysql", map[string]string{
+			"select": "SELECT `Name`,`Age` FROM `User` WHERE Name = ? AND Age > ? LIMIT ?",
+			"insert": "INSERT INTO `User` (`Name`,`Age`) VALUES (?,?),(?,?)",
+			"update": "UPDATE `User` SET `Age` = ? WHERE Name = ?",
+			"upsert": "INSERT INTO `User` (`Name`,`Age`) VALUES (?,?) ON DUPLICATE KEY UPDATE `Age` = VALUES(`Age`)",
+			"ignore": "INSERT INTO `User` (`Name`,`Age`) VALUES (?,?) ON DUPLICATE KEY UPDATE `Name` = `Name`",
+			"ignoreAny": "",
+		})
+	})
+}
+
+func TestClause_Query(t *testing.T) {
+	d, _ := dialect.GetDialect("postgres")
+	clause := New(d)
+	clause.Set(SELECT, "Order", []string{"Kind", "count(*) AS total"}, true)
+	clause.Set(WHERE, Gt("Price", 10))
+	clause.Set(GROUPBY, []string{"Kind"})
+	clause.Set(HAVING, Expr("count(*) > ?", 2))
+	clause.Set(LIMIT, 5)
+	clause.Set(OFFSET, 10)
+	sql, vars := clause.Build(SELECT, WHERE, GROUPBY, HAVING, LIMIT, OFFSET)
+	expect := `SELECT DISTINCT "Kind",count(*) AS total FROM "Order" WHERE "Price" > ? GROUP BY "Kind" HAVING count(*) > ? LIMIT ? OFFSET ?`
+	if sql != expect {
+		t.Fatalf("expect %q, but got %q", expect, sql)
+	}
+	if !reflect.DeepEqual(vars, []interface{}{10, 2, 5, 10}) {
+		t.Fatal("failed to build SQLVars, got", vars)
+	}
+
+	clause = New(d)
+	clause.Set(AGGREGATE, "Order", "SUM", "Price", false)
+	if sql, _ := clause.Build(AGGREGATE); sql != `SELECT SUM("Price") FROM "Order"` {
+		t.Fatal("failed to build aggregate, got", sql)
+	}
+	clause.Set(COUNT, "Order", "Kind", true)
+	if sql, _ := clause.Build(COUNT); sql != `SELECT count(DISTINCT "Kind") FROM "Order"` {
+		t.Fatal("failed to build count, got", sql)
+	}
+}
+
+func TestExpression(t *testing.T) {
+	d, _ := dialect.GetDialect("postgres")
+	clause := New(d)
+	clause.Set(WHERE, And(
+		Eq("Name", "Tom"),
+		Or(Gt("Age", 18), IsNull("Age")),
+		Not(In("Role", []string{"admin", "root"})),
+		Between("Score", 60, 100),
+		Expr("Note LIKE ? OR Note IS NULL", "%go%"),
+	))
+	sql, vars := clause.Build(WHERE)
+	expect := `WHERE "Name" = ? AND ("Age" > ? OR "Age" IS NULL) AND NOT ("Role" IN (?,?)) AND "Score" BETWEEN ? AND ? AND (Note LIKE ? OR Note IS NULL)`
+	if sql != expect {
+		t.Fatalf("expect %q, but got %q", expect, sql)
+	}
+	if !reflect.DeepEqual(vars, []interface{}{"Tom", 18, "admin", "root", 60, 100, "%go%"}) {
+		t.Fatal("failed to build SQLVars, got", vars)
+	}
+}
@@ -0,0 +1,207 @@
+package clause
+
+import (
+	"geeorm/dialect"
+	"reflect"
+	"strings"
+)
+
+// Expression 可组合的查询条件表达式，构造时使用 ? 作为占位符，由Clause统一改写为方言的占位符
+type Expression interface {
+	Build(d dialect.Dialect) (string, []interface{})
+}
+
+// expr 原始SQL片段表达式
+type expr struct {
+	sql  string
+	vars []interface{}
+}
+
+// compare 比较表达式，形如 column op ?
+type compare struct {
+	column string
+	op     string
+	value  interface{}
+}
+
+// in IN 表达式
+type in struct {
+	column string
+	values []interface{}
+}
+
+// between BETWEEN 表达式
+type between struct {
+	column string
+	lo, hi interface{}
+}
+
+// null IS NULL / IS NOT NULL 表达式
+type null struct {
+	column string
+	not    bool
+}
+
+// not NOT 表达式
+type not struct {
+	expr Expression
+}
+
+// junction 使用 AND 或 OR 连接的一组表达式
+type junction struct {
+	op    string
+	exprs []Expression
+}
+
+// Expr 使用原始SQL片段构造表达式
+func Expr(sql string, vars ...interface{}) Expression {
+	return expr{sql: sql, vars: vars}
+}
+
+// Eq column = value
+func Eq(column string, value interface{}) Expression {
+	return compare{column, "=", value}
+}
+
+// Ne column <> value
+func Ne(column string, value interface{}) Expression {
+	return compare{column, "<>", value}
+}
+
+// Gt column > value
+func Gt(column string, value interface{}) Expression {
+	return compare{column, ">", value}
+}
+
+// Gte column >= value
+func Gte(column string, value interface{}) Expression {
+	return compare{column, ">=", value}
+}
+
+// Lt column < value
+func Lt(column string, value interface{}) Expression {
+	return compare{column, "<", value}
+}
+
+// Lte column <= value
+func Lte(column string, value interface{}) Expression {
+	return compare{column, "<=", value}
+}
+
+// Like column LIKE pattern
+func Like(column string, pattern interface{}) Expression {
+	return compare{column, "LIKE", pattern}
+}
+
+// In column IN (values...)，只传入一个切片时会将其展开
+func In(column string, values ...interface{}) Expression {
+	if len(values) == 1 {
+		if v := reflect.ValueOf(values[0]); v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+			values = make([]interface{}, 0, v.Len())
+			for i := 0; i < v.Len(); i++ {
+				values = append(values, v.Index(i).Interface())
+			}
+		}
+	}
+	return in{column, values}
+}
+
+// Between column BETWEEN lo AND hi
+func Between(column string, lo, hi interface{}) Expression {
+	return between{column, lo, hi}
+}
+
+// IsNull column IS NULL
+func IsNull(column string) Expression {
+	return null{column: column}
+}
+
+// NotNull column IS NOT NULL
+func NotNull(column string) Expression {
+	return null{column: column, not: true}
+}
+
+// Not NOT (expr)
+func Not(e Expression) Expression {
+	return not{e}
+}
+
+// And 使用 AND 连接多个表达式，nil 表达式会被忽略
+func And(exprs ...Expression) Expression {
+	return newJunction("AND", exprs)
+}
+
+// Or 使用 OR 连接多个表达式，nil 表达式会被忽略
+func Or(exprs ...Expression) Expression {
+	return newJunction("OR", exprs)
+}
+
+// newJunction 过滤掉nil表达式，仅剩一个表达式时直接返回该表达式
+func newJunction(op string, exprs []Expression) Expression {
+	var list []Expression
+	for _, e := range exprs {
+		if e != nil {
+			list = append(list, e)
+		}
+	}
+	switch len(list) {
+	case 0:
+		return nil
+	case 1:
+		return list[0]
+	}
+	return junction{op, list}
+}
+
+// Build 原样返回SQL片段
+func (e expr) Build(d dialect.Dialect) (string, []interface{}) {
+	return e.sql, e.vars
+}
+
+// Build 构造比较表达式
+func (e compare) Build(d dialect.Dialect) (string, []interface{}) {
+	return quote(d, e.column) + " " + e.op + " ?", []interface{}{e.value}
+}
+
+// Build 构造IN表达式，值列表为空时恒为假
+func (e in) Build(d dialect.Dialect) (string, []interface{}) {
+	if len(e.values) == 0 {
+		return "1 <> 1", nil
+	}
+	return quote(d, e.column) + " IN (" + genBinVars(len(e.values)) + ")", e.values
+}
+
+// Build 构造BETWEEN表达式
+func (e between) Build(d dialect.Dialect) (string, []interface{}) {
+	return quote(d, e.column) + " BETWEEN ? AND ?", []interface{}{e.lo, e.hi}
+}
+
+// Build 构造IS NULL表达式
+func (e null) Build(d dialect.Dialect) (string, []interface{}) {
+	if e.not {
+		return quote(d, e.column) + " IS NOT NULL", nil
+	}
+	return quote(d, e.column) + " IS NULL", nil
+}
+
+// Build 构造NOT表达式
+func (e not) Build(d dialect.Dialect) (string, []interface{}) {
+	sql, vars := e.expr.Build(d)
+	return "NOT (" + sql + ")", vars
+}
+
+// Build 构造连接表达式，原始SQL片段及嵌套的连接表达式会加上括号以保证优先级
+func (e junction) Build(d dialect.Dialect) (string, []interface{}) {
+	var sqls []string
+	var vars []interface{}
+	for _, sub := range e.exprs {
+		sql, subVars := sub.Build(d)
+		switch sub.(type) {
+		case expr, junction:
+			sql = "(" + sql + ")"
+		}
+		sqls = append(sqls, sql)
+		vars = append(vars, subVars...)
+	}
+	return strings.Join(sqls, " "+e.op+" "), vars
+}
@@ -2,10 +2,11 @@ package clause
 
 import (
 	"fmt"
+	"geeorm/dialect"
 	"strings"
 )
 
-type generator func(values ...interface{}) (string, []interface{})
+type generator func(d dialect.Dialect, values ...interface{}) (string, []interface{})
 
 var generators map[Type]generator
 
@@ -21,6 +22,12 @@ func init() {
 	generators[UPDATE] = _update
 	generators[DELETE] = _delete
 	generators[COUNT] = _count
+	generators[ONCONFLICT] = _onConflict
+	generators[RETURNING] = _returning
+	generators[OFFSET] = _offset
+	generators[GROUPBY] = _groupby
+	generators[HAVING] = _having
+	generators[AGGREGATE] = _aggregate
 }
 
 // genBinVars 用来为插入的数据创建占位符字符串
@@ -32,17 +39,38 @@ func genBinVars(num int) string {
 	return strings.Join(vars, ",")
 }
 
+// quote 使用方言为标识符加上引号，未设置方言或标识符为表达式时原样返回
+func quote(d dialect.Dialect, name string) string {
+	if d == nil || name == "" || strings.ContainsAny(name, " ()*'\"`") {
+		return name
+	}
+	parts := strings.Split(name, ".")
+	for i := range parts {
+		parts[i] = d.Quote(parts[i])
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteAll 为一组标识符加上引号
+func quoteAll(d dialect.Dialect, names []string) []string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, quote(d, name))
+	}
+	return quoted
+}
+
 // _insert 构建INSERT语句
 // "INSERT INTO %s (%v)"
-func _insert(values ...interface{}) (string, []interface{}) {
-	name := values[0]
-	fields := strings.Join(values[1].([]string), ",")
+func _insert(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	name := quote(d, values[0].(string))
+	fields := strings.Join(quoteAll(d, values[1].([]string)), ",")
 	return fmt.Sprintf("INSERT INTO %s (%v)", name, fields), []interface{}{}
 }
 
 // _values 构造INSERT语句中的VALUES字段
 // "VALUES (?, ?, ...,?), (...) v, v, ..., v"
-func _values(values ...interface{}) (string, []interface{}) {
+func _values(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
 	var sql strings.Builder
 	var sqlvars []interface{}
 	var binStr string
@@ -62,55 +90,118 @@ func _values(values ...interface{}) (string, []interface{}) {
 	return sql.String(), sqlvars
 }
 
-// _select 构造SELECT语句
+// _select 构造SELECT语句，可选的第三个参数为 true 时去除重复的行
 // "SLEECT %v FROM %s"
-func _select(values ...interface{}) (string, []interface{}) {
-	name := values[0]
-	vars := strings.Join(values[1].([]string), ",")
+func _select(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	name := quote(d, values[0].(string))
+	vars := strings.Join(quoteAll(d, values[1].([]string)), ",")
+	if len(values) > 2 && values[2].(bool) {
+		vars = "DISTINCT " + vars
+	}
 	return fmt.Sprintf("SELECT %v FROM %s", vars, name), []interface{}{}
 }
 
 // _limit 构造LIMIT语句
 // “LIMIT ?”
-func _limit(values ...interface{}) (string, []interface{}) {
+func _limit(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
 	return "LIMIT ?", values
 }
 
-// _where 构造WHERE语句
+// _where 构造WHERE语句，条件可以是原始SQL片段及其参数，也可以是一个Expression
 // "WHERE %s"
-func _where(values ...interface{}) (string, []interface{}) {
+func _where(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	if expr, ok := values[0].(Expression); ok {
+		sql, vars := expr.Build(d)
+		return fmt.Sprintf("WHERE %s", sql), vars
+	}
 	desc, vars := values[0], values[1:]
 	return fmt.Sprintf("WHERE %s", desc), vars
 }
 
 // _orderby 构造 ORDER BY 语句
 //  "ORDER BY %s"
-func _orderby(values ...interface{}) (string, []interface{}) {
+func _orderby(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
 	return fmt.Sprintf("ORDER BY %s", values[0]), []interface{}{}
 }
 
 // _update 构造UPDATE语句 
 // "UPDATE %s SET %s"
-func _update(values ...interface{}) (string, []interface{}) {
-	name := values[0]
+func _update(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	name := quote(d, values[0].(string))
 	var vars []interface{}
 	var keys []string
 	m := values[1].(map[string]interface{})
 	for k, v := range m {
+		// 值为表达式时直接写入表达式，例如 version = version + 1
+		if expr, ok := v.(Expression); ok {
+			sql, exprVars := expr.Build(d)
+			keys = append(keys, quote(d, k)+" = "+sql)
+			vars = append(vars, exprVars...)
+			continue
+		}
 		vars = append(vars, v)
-		keys = append(keys, k + " = ?")
+		keys = append(keys, quote(d, k) + " = ?")
 	}
 	return fmt.Sprintf("UPDATE %s SET %s", name, strings.Join(keys, ",")), vars
 }
 
 // _delete 构造DELETE语句
 // "DELETE FROM %s"
-func _delete(values ...interface{}) (string, []interface{}) {
-	return fmt.Sprintf("DELETE FROM %s", values[0]), []interface{}{}
+func _delete(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	return fmt.Sprintf("DELETE FROM %s", quote(d, values[0].(string))), []interface{}{}
 }
 
-// _count 构造COUNT语句
+// _count 构造COUNT语句，可选的参数依次为统计的列名及是否只统计不重复的值
 // "SELECT count(*) FROM %s"
-func _count(values ...interface{}) (string, []interface{}) {
-	return _select(values[0], []string{"count(*)"})
+func _count(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	if len(values) == 1 {
+		return _select(d, values[0], []string{"count(*)"})
+	}
+	return _aggregate(d, append([]interface{}{values[0], "count"}, values[1:]...)...)
+}
+
+// _onConflict 构造INSERT语句的冲突处理语句，具体语法由方言决定
+// "ON CONFLICT (%s) DO UPDATE SET %s" 或 "ON DUPLICATE KEY UPDATE %s"
+func _onConflict(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	conflict := values[0].(OnConflict)
+	updates := conflict.DoUpdates
+	if conflict.DoNothing {
+		updates = nil
+	}
+	return d.OnConflictSQL(conflict.Columns, updates), []interface{}{}
+}
+
+// _returning 构造INSERT语句返回插入行的语句，具体语法由方言决定
+// "RETURNING %s"
+func _returning(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	return d.ReturningSQL(values[0].(string)), []interface{}{}
+}
+
+// _offset 构造OFFSET语句
+// "OFFSET ?"
+func _offset(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	return "OFFSET ?", values
+}
+
+// _groupby 构造GROUP BY语句
+// "GROUP BY %s"
+func _groupby(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	return fmt.Sprintf("GROUP BY %s", strings.Join(quoteAll(d, values[0].([]string)), ",")), []interface{}{}
+}
+
+// _having 构造HAVING语句，条件的形式与WHERE相同
+// "HAVING %s"
+func _having(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	sql, vars := _where(d, values...)
+	return "HAVING" + strings.TrimPrefix(sql, "WHERE"), vars
+}
+
+// _aggregate 构造聚合查询语句，参数依次为表名、聚合函数、列名及可选的是否只统计不重复的值
+// "SELECT %s(%s) FROM %s"
+func _aggregate(d dialect.Dialect, values ...interface{}) (string, []interface{}) {
+	column := quote(d, values[2].(string))
+	if len(values) > 3 && values[3].(bool) {
+		column = "DISTINCT " + column
+	}
+	return _select(d, values[0], []string{fmt.Sprintf("%s(%s)", values[1], column)})
 }
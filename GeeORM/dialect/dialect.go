@@ -1,8 +1,10 @@
 package dialect
 
 import (
+	"database/sql/driver"
 	"geeorm/log"
 	"reflect"
+	"strings"
 )
 
 var dialectsMap = map[string]Dialect{}
@@ -12,6 +14,38 @@ var dialectsMap = map[string]Dialect{}
 type Dialect interface {
 	DataTypeOf(typ reflect.Value) string
 	TableExistSQL(tableName string) (string, []interface{}) 
+	// AutoIncrement 返回自增列实际使用的列类型及需要追加在主键约束之后的关键字
+	AutoIncrement(dataType string) (string, string)
+	// Quote 为表名、列名等标识符加上引号
+	Quote(name string) string
+	// BindVar 返回第index个(从1开始)参数的占位符
+	BindVar(index int) string
+	// SavepointSQL 创建保存点的语句
+	SavepointSQL(name string) string
+	// ReleaseSavepointSQL 释放保存点的语句
+	ReleaseSavepointSQL(name string) string
+	// RollbackToSavepointSQL 回滚到保存点的语句
+	RollbackToSavepointSQL(name string) string
+	// ColumnsSQL 查询表中各列定义的语句，结果依次为列名、类型、是否非空、默认值、是否为主键
+	// 返回空语句表示方言不支持该查询
+	ColumnsSQL(tableName string) (string, []interface{})
+	// IndexesSQL 查询表上索引的语句，结果依次为索引名、是否唯一、是否由约束隐式创建、列名
+	// 每行对应索引中的一列，同一索引的各列按索引中的顺序相邻返回，返回空语句表示方言不支持该查询
+	IndexesSQL(tableName string) (string, []interface{})
+	// TablesSQL 查询数据库中全部用户表表名的语句，返回空语句表示方言不支持该查询
+	TablesSQL() (string, []interface{})
+	// ForeignKeysSQL 查询表上外键的语句，结果依次为外键编号、列名、引用的表、引用的列、更新时动作、删除时动作
+	// 每行对应外键中的一列，同一外键的各列按顺序相邻返回，返回空语句表示方言不支持该查询
+	ForeignKeysSQL(tableName string) (string, []interface{})
+	// OnConflictSQL 追加在INSERT语句之后的冲突处理语句，columns 为判断冲突的列
+	// updates 为冲突时使用插入的值更新的列，为空时忽略冲突的行
+	OnConflictSQL(columns []string, updates []string) string
+	// MaxBindVars 单条语句允许绑定的最大参数个数
+	MaxBindVars() int
+	// ReturningSQL 追加在INSERT语句之后返回插入行中 column 列的语句，返回空语句表示不支持，此时使用 LastInsertId
+	ReturningSQL(column string) string
+	// LastInsertIDIsFirst 多行插入时 LastInsertId 返回的是否为第一行的自增值，否则为最后一行的自增值
+	LastInsertIDIsFirst() bool
 }
 
 // RegisterDialect 注册方言方法，将方言及名称存放在hash表中
@@ -27,4 +61,77 @@ func GetDialect(name string) (d Dialect, ok bool) {
 	return
 }
 
+// Rebind 将查询语句中的 ? 占位符替换为方言对应的占位符，引号内的 ? 不做替换
+func Rebind(d Dialect, query string) string {
+	if d.BindVar(1) == "?" {
+		return query
+	}
+	var sql strings.Builder
+	var quote rune
+	index := 0
+	for _, c := range query {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			index++
+			sql.WriteString(d.BindVar(index))
+			continue
+		}
+		sql.WriteRune(c)
+	}
+	return sql.String()
+}
+
+// onConflict 生成 ON CONFLICT 形式的冲突处理语句，excluded 为引用插入值的伪表名
+func onConflict(d Dialect, columns []string, updates []string, excluded string) string {
+	var sql strings.Builder
+	sql.WriteString("ON CONFLICT")
+	if len(columns) > 0 {
+		quoted := make([]string, 0, len(columns))
+		for _, col := range columns {
+			quoted = append(quoted, d.Quote(col))
+		}
+		sql.WriteString(" (" + strings.Join(quoted, ",") + ")")
+	}
+	if len(updates) == 0 {
+		sql.WriteString(" DO NOTHING")
+		return sql.String()
+	}
+	sets := make([]string, 0, len(updates))
+	for _, col := range updates {
+		sets = append(sets, d.Quote(col)+" = "+excluded+"."+d.Quote(col))
+	}
+	sql.WriteString(" DO UPDATE SET " + strings.Join(sets, ","))
+	return sql.String()
+}
 
+// valuerDataType 为实现了 driver.Valuer 的类型推断列类型，Valuer 的优先级高于类型本身的 Kind
+// 使用零值的 Value 返回值的类型映射，返回 nil 时若结构体为 sql.NullString 形式(值字段与 Valid 字段)则使用值字段的类型
+func valuerDataType(d Dialect, typ reflect.Value) (string, bool) {
+	t := typ.Type()
+	valuer, ok := reflect.New(t).Interface().(driver.Valuer)
+	if !ok {
+		return "", false
+	}
+	if v, err := valuer.Value(); err == nil && v != nil && reflect.TypeOf(v) != t {
+		return d.DataTypeOf(reflect.ValueOf(v)), true
+	}
+	if t.Kind() == reflect.Struct && t.NumField() == 2 && t.Field(1).Name == "Valid" && t.Field(1).Type.Kind() == reflect.Bool {
+		return d.DataTypeOf(reflect.New(t.Field(0).Type).Elem()), true
+	}
+	if t.Kind() == reflect.Struct {
+		return d.DataTypeOf(reflect.ValueOf("")), true
+	}
+	// 无法从零值推断时按类型本身的 Kind 映射
+	return "", false
+}
+
+// quoteIdent 用给定的引号包裹标识符，标识符中出现的引号会被转义
+func quoteIdent(name string, quote string) string {
+	return quote + strings.Replace(name, quote, quote+quote, -1) + quote
+}
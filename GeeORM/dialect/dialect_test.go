@@ -0,0 +1,43 @@
+package dialect
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDataTypeOf(t *testing.T) {
+	cases := []struct {
+		dialect string
+		value   interface{}
+		expect  string
+	}{
+		{"sqlite3", int64(0), "bigint"},
+		{"sqlite3", time.Time{}, "datetime"},
+		{"postgres", 0, "integer"},
+		{"postgres", 0.0, "double precision"},
+		{"postgres", []byte{}, "bytea"},
+		{"postgres", time.Time{}, "timestamptz"},
+		{"mysql", "", "varchar(255)"},
+		{"mysql", uint32(0), "int unsigned"},
+		{"mysql", time.Time{}, "datetime(3)"},
+	}
+	for _, c := range cases {
+		d, _ := GetDialect(c.dialect)
+		if typ := d.DataTypeOf(reflect.ValueOf(c.value)); typ != c.expect {
+			t.Fatalf("%s: expect %s, but got %s", c.dialect, c.expect, typ)
+		}
+	}
+}
+
+func TestRebind(t *testing.T) {
+	d, _ := GetDialect("postgres")
+	sql := Rebind(d, "SELECT * FROM \"a?\" WHERE Name = ? AND Note <> '?' AND Age IN (?,?)")
+	if sql != "SELECT * FROM \"a?\" WHERE Name = $1 AND Note <> '?' AND Age IN ($2,$3)" {
+		t.Fatal("failed to rebind, got", sql)
+	}
+	d, _ = GetDialect("mysql")
+	if sql := Rebind(d, "Name = ?"); sql != "Name = ?" {
+		t.Fatal("failed to rebind, got", sql)
+	}
+}
@@ -0,0 +1,33 @@
+package dialect
+
+import "errors"
+
+// ErrNotSupported 方言尚未支持的操作
+var ErrNotSupported = errors.New("geeorm: operation is not supported by the dialect")
+
+// Column 数据库中一列的实际定义
+type Column struct {
+	Name       string
+	Type       string
+	NotNull    bool
+	Default    *string // 默认值表达式，没有默认值时为 nil
+	PrimaryKey bool
+}
+
+// Index 数据库中一个索引的实际定义
+type Index struct {
+	Name    string
+	Unique  bool
+	Columns []string
+	// Implicit 为 true 时索引由 UNIQUE、PRIMARY KEY 等约束隐式创建，而不是 CREATE INDEX 语句
+	Implicit bool
+}
+
+// ForeignKey 数据库中一个外键的实际定义
+type ForeignKey struct {
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnUpdate   string
+	OnDelete   string
+}
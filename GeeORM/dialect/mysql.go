@@ -0,0 +1,145 @@
+package dialect
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var _ Dialect = (*mysql)(nil)
+
+// 在dialect包的初始化函数中注册符合mysql规则的方言接口
+func init() {
+	RegisterDialect("mysql", &mysql{})
+}
+
+// mysql 的方言接口载体，为其实现符合mysql规则的类型映射方法及其他相关功能
+type mysql struct{}
+
+// DataTypeOf 为mysql实现类型映射方法
+func (m *mysql) DataTypeOf(typ reflect.Value) string {
+	if dataType, ok := valuerDataType(m, typ); ok {
+		return dataType
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int8:
+		return "tinyint"
+	case reflect.Int16:
+		return "smallint"
+	case reflect.Int, reflect.Int32:
+		return "int"
+	case reflect.Int64:
+		return "bigint"
+	case reflect.Uint8:
+		return "tinyint unsigned"
+	case reflect.Uint16:
+		return "smallint unsigned"
+	case reflect.Uint, reflect.Uint32:
+		return "int unsigned"
+	case reflect.Uint64, reflect.Uintptr:
+		return "bigint unsigned"
+	case reflect.Float32:
+		return "float"
+	case reflect.Float64:
+		return "double"
+	case reflect.String:
+		return "varchar(255)"
+	case reflect.Array, reflect.Slice:
+		return "longblob"
+	case reflect.Struct:
+		if _, ok := typ.Interface().(time.Time); ok {
+			return "datetime(3)"
+		}
+	}
+	panic(fmt.Sprintf("invalid sql type %s (%s)", typ.Type().Name(), typ.Kind()))
+}
+
+// TableExistSQL 为mysql实现判断某个表tableName是否存在的SQL语句
+func (m *mysql) TableExistSQL(tableName string) (string, []interface{}) {
+	args := []interface{}{tableName}
+	return "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?", args
+}
+
+// AutoIncrement mysql 在列定义中追加 AUTO_INCREMENT 关键字
+func (m *mysql) AutoIncrement(dataType string) (string, string) {
+	return dataType, "AUTO_INCREMENT"
+}
+
+// Quote mysql 使用反引号包裹标识符
+func (m *mysql) Quote(name string) string {
+	return quoteIdent(name, "`")
+}
+
+// BindVar mysql 使用 ? 作为占位符
+func (m *mysql) BindVar(index int) string {
+	return "?"
+}
+
+// SavepointSQL mysql 创建保存点的语句
+func (m *mysql) SavepointSQL(name string) string {
+	return "SAVEPOINT " + name
+}
+
+// ReleaseSavepointSQL mysql 释放保存点的语句
+func (m *mysql) ReleaseSavepointSQL(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+// RollbackToSavepointSQL mysql 回滚到保存点的语句
+func (m *mysql) RollbackToSavepointSQL(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// ColumnsSQL mysql 暂不支持查询列定义
+func (m *mysql) ColumnsSQL(tableName string) (string, []interface{}) {
+	return "", nil
+}
+
+// IndexesSQL mysql 暂不支持查询索引
+func (m *mysql) IndexesSQL(tableName string) (string, []interface{}) {
+	return "", nil
+}
+
+// TablesSQL mysql 暂不支持查询表名
+func (m *mysql) TablesSQL() (string, []interface{}) {
+	return "", nil
+}
+
+// ForeignKeysSQL mysql 暂不支持查询外键
+func (m *mysql) ForeignKeysSQL(tableName string) (string, []interface{}) {
+	return "", nil
+}
+
+// OnConflictSQL mysql 使用 ON DUPLICATE KEY UPDATE 处理冲突，冲突由主键及唯一索引判断，columns 仅用于忽略冲突
+// 忽略冲突时将第一列更新为自身，使冲突的行保持不变，没有可用的列时返回空语句表示不支持
+func (m *mysql) OnConflictSQL(columns []string, updates []string) string {
+	if len(updates) == 0 {
+		if len(columns) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("ON DUPLICATE KEY UPDATE %s = %s", m.Quote(columns[0]), m.Quote(columns[0]))
+	}
+	sets := make([]string, 0, len(updates))
+	for _, col := range updates {
+		sets = append(sets, fmt.Sprintf("%s = VALUES(%s)", m.Quote(col), m.Quote(col)))
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ",")
+}
+
+// MaxBindVars mysql 的预处理语句最多允许绑定 65535 个参数
+func (m *mysql) MaxBindVars() int {
+	return 65535
+}
+
+// ReturningSQL mysql 不支持 RETURNING，通过 LastInsertId 获取自增值
+func (m *mysql) ReturningSQL(column string) string {
+	return ""
+}
+
+// LastInsertIDIsFirst mysql 的 LastInsertId 为多行插入中第一行的自增值
+func (m *mysql) LastInsertIDIsFirst() bool {
+	return true
+}
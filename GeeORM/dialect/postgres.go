@@ -0,0 +1,130 @@
+package dialect
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var _ Dialect = (*postgres)(nil)
+
+// 在dialect包的初始化函数中注册符合postgres规则的方言接口
+func init() {
+	RegisterDialect("postgres", &postgres{})
+}
+
+// postgres 的方言接口载体，为其实现符合postgres规则的类型映射方法及其他相关功能
+type postgres struct{}
+
+// DataTypeOf 为postgres实现类型映射方法
+func (p *postgres) DataTypeOf(typ reflect.Value) string {
+	if dataType, ok := valuerDataType(p, typ); ok {
+		return dataType
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return "smallint"
+	case reflect.Int, reflect.Int32, reflect.Uint16:
+		return "integer"
+	case reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "bigint"
+	case reflect.Float32:
+		return "real"
+	case reflect.Float64:
+		return "double precision"
+	case reflect.String:
+		return "text"
+	case reflect.Array, reflect.Slice:
+		return "bytea"
+	case reflect.Struct:
+		if _, ok := typ.Interface().(time.Time); ok {
+			return "timestamptz"
+		}
+	}
+	panic(fmt.Sprintf("invalid sql type %s (%s)", typ.Type().Name(), typ.Kind()))
+}
+
+// TableExistSQL 为postgres实现判断某个表tableName是否存在的SQL语句
+func (p *postgres) TableExistSQL(tableName string) (string, []interface{}) {
+	args := []interface{}{tableName}
+	return "SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename = ?", args
+}
+
+// AutoIncrement postgres 使用 serial 系列类型实现自增
+func (p *postgres) AutoIncrement(dataType string) (string, string) {
+	switch dataType {
+	case "smallint":
+		return "smallserial", ""
+	case "integer":
+		return "serial", ""
+	}
+	return "bigserial", ""
+}
+
+// Quote postgres 使用双引号包裹标识符
+func (p *postgres) Quote(name string) string {
+	return quoteIdent(name, `"`)
+}
+
+// BindVar postgres 使用 $1, $2... 作为占位符
+func (p *postgres) BindVar(index int) string {
+	return "$" + strconv.Itoa(index)
+}
+
+// SavepointSQL postgres 创建保存点的语句
+func (p *postgres) SavepointSQL(name string) string {
+	return "SAVEPOINT " + name
+}
+
+// ReleaseSavepointSQL postgres 释放保存点的语句
+func (p *postgres) ReleaseSavepointSQL(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+// RollbackToSavepointSQL postgres 回滚到保存点的语句
+func (p *postgres) RollbackToSavepointSQL(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// ColumnsSQL postgres 暂不支持查询列定义
+func (p *postgres) ColumnsSQL(tableName string) (string, []interface{}) {
+	return "", nil
+}
+
+// IndexesSQL postgres 暂不支持查询索引
+func (p *postgres) IndexesSQL(tableName string) (string, []interface{}) {
+	return "", nil
+}
+
+// TablesSQL postgres 暂不支持查询表名
+func (p *postgres) TablesSQL() (string, []interface{}) {
+	return "", nil
+}
+
+// ForeignKeysSQL postgres 暂不支持查询外键
+func (p *postgres) ForeignKeysSQL(tableName string) (string, []interface{}) {
+	return "", nil
+}
+
+// OnConflictSQL postgres 使用 ON CONFLICT ... DO UPDATE/DO NOTHING 处理冲突
+func (p *postgres) OnConflictSQL(columns []string, updates []string) string {
+	return onConflict(p, columns, updates, "EXCLUDED")
+}
+
+// MaxBindVars postgres 的协议最多允许绑定 65535 个参数
+func (p *postgres) MaxBindVars() int {
+	return 65535
+}
+
+// ReturningSQL postgres 通过 RETURNING 返回自增值
+func (p *postgres) ReturningSQL(column string) string {
+	return "RETURNING " + p.Quote(column)
+}
+
+// LastInsertIDIsFirst postgres 不支持 LastInsertId，使用 RETURNING 获取自增值
+func (p *postgres) LastInsertIDIsFirst() bool {
+	return false
+}
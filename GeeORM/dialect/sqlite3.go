@@ -19,6 +19,9 @@ type sqlite3 struct{}
 
 // DataTypeOf 为sqlite3实现类型映射方法
 func (s *sqlite3) DataTypeOf(typ reflect.Value) string {
+	if dataType, ok := valuerDataType(s, typ); ok {
+		return dataType
+	}
 	switch typ.Kind() {
 	case reflect.Bool:
 		return "bool"
@@ -47,5 +50,74 @@ func (s *sqlite3) TableExistSQL(tableName string) (string, []interface{}) {
 	return "SELECT name FROM sqlite_master WHERE type='table' and name = ?", args
 }
 
+// AutoIncrement sqlite3 仅允许 INTEGER PRIMARY KEY 列使用 AUTOINCREMENT
+func (s *sqlite3) AutoIncrement(dataType string) (string, string) {
+	return "integer", "AUTOINCREMENT"
+}
+
+// Quote sqlite3 使用双引号包裹标识符
+func (s *sqlite3) Quote(name string) string {
+	return quoteIdent(name, `"`)
+}
+
+// BindVar sqlite3 使用 ? 作为占位符
+func (s *sqlite3) BindVar(index int) string {
+	return "?"
+}
+
+// SavepointSQL sqlite3 创建保存点的语句
+func (s *sqlite3) SavepointSQL(name string) string {
+	return "SAVEPOINT " + name
+}
+
+// ReleaseSavepointSQL sqlite3 释放保存点的语句
+func (s *sqlite3) ReleaseSavepointSQL(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+// RollbackToSavepointSQL sqlite3 回滚到保存点的语句
+func (s *sqlite3) RollbackToSavepointSQL(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// ColumnsSQL sqlite3 通过 pragma_table_info 查询列定义
+func (s *sqlite3) ColumnsSQL(tableName string) (string, []interface{}) {
+	return `SELECT name, type, "notnull", dflt_value, pk > 0 FROM pragma_table_info(?) ORDER BY cid`, []interface{}{tableName}
+}
+
+// IndexesSQL sqlite3 通过 pragma_index_list 与 pragma_index_info 查询索引
+func (s *sqlite3) IndexesSQL(tableName string) (string, []interface{}) {
+	return `SELECT il.name, il."unique", il.origin <> 'c', ii.name FROM pragma_index_list(?) AS il, ` +
+		`pragma_index_info(il.name) AS ii ORDER BY il.name, ii.seqno`, []interface{}{tableName}
+}
 
+// TablesSQL sqlite3 从 sqlite_master 中查询表名，排除 sqlite 内部使用的表
+func (s *sqlite3) TablesSQL() (string, []interface{}) {
+	return "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite\\_%' ESCAPE '\\' ORDER BY name", nil
+}
 
+// ForeignKeysSQL sqlite3 通过 pragma_foreign_key_list 查询外键
+func (s *sqlite3) ForeignKeysSQL(tableName string) (string, []interface{}) {
+	return `SELECT id, "from", "table", "to", on_update, on_delete FROM pragma_foreign_key_list(?) ORDER BY id, seq`,
+		[]interface{}{tableName}
+}
+
+// OnConflictSQL sqlite3 使用 ON CONFLICT ... DO UPDATE/DO NOTHING 处理冲突
+func (s *sqlite3) OnConflictSQL(columns []string, updates []string) string {
+	return onConflict(s, columns, updates, "excluded")
+}
+
+// MaxBindVars sqlite3 在 3.32.0 之前默认最多允许绑定 999 个参数
+func (s *sqlite3) MaxBindVars() int {
+	return 999
+}
+
+// ReturningSQL sqlite3 暂不使用 RETURNING，通过 LastInsertId 获取自增值
+func (s *sqlite3) ReturningSQL(column string) string {
+	return ""
+}
+
+// LastInsertIDIsFirst sqlite3 的 LastInsertId 为最后插入的一行的自增值
+func (s *sqlite3) LastInsertIDIsFirst() bool {
+	return false
+}
@@ -1,18 +1,21 @@
 package geeorm
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
 	"geeorm/dialect"
 	"geeorm/log"
+	"geeorm/schema"
 	"geeorm/session"
-	"strings"
+	"time"
 )
 
 // Engine 数据库访问引擎
 type Engine struct {
 	db *sql.DB
 	dial dialect.Dialect
+	nowFunc func() time.Time
+	naming schema.NamingStrategy
 }
 
 // NewEngine 创建新的数据库访问连接，并Ping数据库
@@ -36,6 +39,11 @@ func NewEngine(driver, source string) (e *Engine, err error) {
 	return 
 }
 
+// Dialect 返回引擎所连接数据库的方言
+func (e *Engine) Dialect() dialect.Dialect {
+	return e.dial
+}
+
 // Close 关闭数据库访问连接
 func (e *Engine) Close() {
 	err := e.db.Close()
@@ -47,77 +55,29 @@ func (e *Engine) Close() {
 
 // NewSession 创建新的数据库访问会话
 func (e *Engine) NewSession() *session.Session {
-	return session.New(e.db, e.dial)
+	return session.New(e.db, e.dial).SetNowFunc(e.nowFunc).SetNamingStrategy(e.naming)
+}
+
+// SetNowFunc 设置引擎创建的会话获取当前时间的函数，用于自动时间戳与软删除
+func (e *Engine) SetNowFunc(f func() time.Time) {
+	e.nowFunc = f
+}
+
+// SetNamingStrategy 设置引擎创建的会话映射表名与列名时使用的命名策略，默认原样使用类型名与成员变量名
+func (e *Engine) SetNamingStrategy(naming schema.NamingStrategy) {
+	e.naming = naming
 }
 
 // TxFunc 事务函数模板
-type TxFunc func(*session.Session) (reslut interface{}, err error)
+type TxFunc = session.TxFunc
 
 // Transaction 事务接口
 func (e *Engine) Transaction(f TxFunc) (result interface{}, err error) {
-	s := e.NewSession()
-	if err := s.Begin(); err != nil {
-		return nil, err
-	}
-	defer func() {
-		if p := recover(); p != nil {
-			_ = s.Rollback()
-			panic(p)
-		} else if err != nil {
-			_ = s.Rollback()
-		} else {
-			_ = s.Commit()
-		} 
-	}()
-	return f(s)
+	return e.TransactionContext(context.Background(), f)
 }
 
-// difference 用来比较两个字符串数组的差异 返回 a-b
-func difference (a []string, b []string) []string {
-	mapB := make(map[string]bool)
-	for _, v := range b {
-		mapB[v] = true
-	}
-	res := make([]string, 0)
-	for _, v := range a {
-		if _, ok := mapB[v]; !ok {
-			res = append(res, v)
-		}
-	}
-	return res
-} 
-
-// Migrate 数据库迁移操作
-// 根据输入的对象类型名查找对应的表，比对条目的差异，先增加新增的列，创建一张新表迁移旧表，再删除旧表，修改新表名
-func (e *Engine) Migrate(value interface{}) error {
-	_ ,err := e.Transaction(func(s *session.Session) (result interface{}, err error) {
-		if !s.Model(value).HasTable() {
-			log.Infof("table %s is not exists", s.GetrefTable().Name)
-			return nil, s.CreateTable()
-		}		
-		table := s.GetrefTable()
-		rows, _ := s.Raw(fmt.Sprintf("SELECT * FROM %s LIMIT 1", table.Name)).QueryRows()
-		columns, _ := rows.Columns()
-		addCols := difference(table.FieldNames, columns)
-		delCols := difference(columns, table.FieldNames)
-		log.Infof("added cols %v, deleted cols %v", addCols, delCols)
-		for _, col := range addCols {
-			f := table.GetField(col)
-			if _, err = s.Raw(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", table.Name,f.Name, f.Type)).Exec(); err != nil {
-				return
-			}
-		}
-		
-		if len(delCols) == 0 {
-			return
-		}
-		tmp := "Tmp_" + table.Name
-		fieldStr := strings.Join(table.FieldNames, ",")
-		s.Raw(fmt.Sprintf("CREATE TABLE %s AS SELECT %s from %s;", tmp, fieldStr, table.Name))
-		s.Raw(fmt.Sprintf("DROP TABLE %s;", table.Name))
-		s.Raw(fmt.Sprintf("ALTER TABLE %s RENMAE TO %s;", tmp, table.Name))
-		_, err = s.Exec()
-		return
-	})
-	return err
-}
\ No newline at end of file
+// TransactionContext 在给定上下文中执行事务，上下文取消时事务中的操作将失败并回滚
+// 需要嵌套事务时，在事务函数中调用 Session.Transaction 即可以保存点的形式加入外层事务
+func (e *Engine) TransactionContext(ctx context.Context, f TxFunc) (result interface{}, err error) {
+	return e.NewSession().WithContext(ctx).Transaction(f)
+}
@@ -1,9 +1,13 @@
 package geeorm
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"geeorm/dialect"
 	"geeorm/session"
 	"reflect"
+	"strings"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -38,6 +42,9 @@ func TestEngine_Transcation(t *testing.T) {
 	t.Run("commit", func(t *testing.T) {
 		transactionCommit(t)
 	})
+	t.Run("context", func(t *testing.T) {
+		transactionContext(t)
+	})
 }
 
 func transactionRollback(t *testing.T) {
@@ -72,6 +79,19 @@ func transactionCommit(t *testing.T) {
 	}
 }
 
+func transactionContext(t *testing.T) {
+	engine := OpenDB(t)
+	defer engine.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := engine.TransactionContext(ctx, func(s *session.Session) (result interface{}, err error) {
+		cancel()
+		return s.Model(&User{}).Count()
+	})
+	if err == nil {
+		t.Fatal("expect the canceled transaction to fail")
+	}
+}
+
 func TestEngine_Migrate(t *testing.T) {
 	engine := OpenDB(t)
 	s := engine.NewSession()
@@ -85,4 +105,134 @@ func TestEngine_Migrate(t *testing.T) {
 	if !reflect.DeepEqual(columns, []string{"Name", "Age"}) {
 		t.Fatal("failed to migraet table user, got columns",columns)
 	}
-}
\ No newline at end of file
+}
+type Book struct {
+	ID     int    `geeorm:"primaryKey"`
+	Title  string `geeorm:"not null;default:'';index"`
+	Price  float64
+	Author string
+}
+
+func TestEngine_Plan(t *testing.T) {
+	engine := OpenDB(t)
+	defer engine.Close()
+	s := engine.NewSession()
+	_, _ = s.Raw("DROP TABLE IF EXISTS Book;").Exec()
+	_, _ = s.Raw("CREATE TABLE Book (ID integer PRIMARY KEY, Title text, Price integer, Obsolete text);").Exec()
+	_, _ = s.Raw("INSERT INTO Book (ID, Title, Price) VALUES (1, 'Go', 10), (2, NULL, 5);").Exec()
+
+	var buf bytes.Buffer
+	plan, err := engine.DryRun(&buf, &Book{})
+	if err != nil || len(plan.Statements) == 0 || buf.String() != plan.String() {
+		t.Fatal("failed to plan migration", err)
+	}
+	if !s.TableExists("Book") || s.TableExists("geeorm_tmp_Book") {
+		t.Fatal("dry run should not change the database")
+	}
+	if !strings.HasPrefix(plan.Statements[0], `CREATE TABLE "geeorm_tmp_Book"`) {
+		t.Fatal("expect table rebuild, got", plan.Statements)
+	}
+	if err := engine.Migrate(&Book{}); err != nil {
+		t.Fatal("failed to migrate", err)
+	}
+	if plan, err = engine.Plan(&Book{}); err != nil || len(plan.Statements) != 0 {
+		t.Fatal("expect empty plan after migration, got", plan, err)
+	}
+	var title string
+	var price float64
+	if err := s.Raw("SELECT Title, Price FROM Book WHERE ID = 1").QueryRow().Scan(&title, &price); err != nil || title != "Go" || price != 10 {
+		t.Fatal("failed to keep data while rebuilding table", err)
+	}
+	if err := s.Raw("SELECT Title FROM Book WHERE ID = 2").QueryRow().Scan(&title); err != nil || title != "" {
+		t.Fatal("expect NULL to be replaced by the default, got", title, err)
+	}
+	indexes, _ := s.Indexes("Book")
+	if len(indexes) != 1 || indexes[0].Name != "idx_Book_Title" {
+		t.Fatal("failed to create index, got", indexes)
+	}
+}
+
+func TestEngine_Inspect(t *testing.T) {
+	engine := OpenDB(t)
+	defer engine.Close()
+	s := engine.NewSession()
+	_, _ = s.Raw("DROP TABLE IF EXISTS Chapter;").Exec()
+	_, _ = s.Raw("DROP TABLE IF EXISTS Volume;").Exec()
+	_, _ = s.Raw("CREATE TABLE Volume (ID integer PRIMARY KEY, Title text NOT NULL DEFAULT 'untitled');").Exec()
+	_, _ = s.Raw("CREATE TABLE Chapter (ID integer PRIMARY KEY, VolumeID integer REFERENCES Volume (ID) ON DELETE CASCADE);").Exec()
+	_, _ = s.Raw("CREATE INDEX idx_chapter_volume ON Chapter (VolumeID);").Exec()
+
+	inspector := engine.Inspect()
+	tables, err := inspector.Tables()
+	if err != nil || !inspector.HasTable("Volume") {
+		t.Fatal("failed to list tables", err)
+	}
+	for _, name := range tables {
+		if strings.HasPrefix(name, "sqlite_") {
+			t.Fatal("expect internal tables to be skipped, got", name)
+		}
+	}
+	columns, err := inspector.Columns("Volume")
+	if err != nil || len(columns) != 2 || !columns[0].PrimaryKey || !columns[1].NotNull ||
+		columns[1].Default == nil || *columns[1].Default != "'untitled'" {
+		t.Fatal("failed to describe columns", columns, err)
+	}
+	indexes, err := inspector.Indexes("Chapter")
+	if err != nil || len(indexes) != 1 || indexes[0].Implicit || indexes[0].Columns[0] != "VolumeID" {
+		t.Fatal("failed to list indexes", indexes, err)
+	}
+	keys, err := inspector.ForeignKeys("Chapter")
+	if err != nil || len(keys) != 1 || keys[0].RefTable != "Volume" || keys[0].Columns[0] != "VolumeID" ||
+		keys[0].RefColumns[0] != "ID" || keys[0].OnDelete != "CASCADE" {
+		t.Fatal("failed to list foreign keys", keys, err)
+	}
+}
+
+type Ticket struct {
+	ID    int    `geeorm:"primaryKey"`
+	Title string `geeorm:"not null"`
+}
+
+func TestEngine_PlanNotNull(t *testing.T) {
+	engine := OpenDB(t)
+	defer engine.Close()
+	s := engine.NewSession()
+	_, _ = s.Raw("DROP TABLE IF EXISTS Ticket;").Exec()
+	_, _ = s.Raw("CREATE TABLE Ticket (ID integer PRIMARY KEY, Title text);").Exec()
+	_, _ = s.Raw("INSERT INTO Ticket (ID, Title) VALUES (1, NULL);").Exec()
+	if _, err := engine.Plan(&Ticket{}); err == nil {
+		t.Fatal("expect error for NULL values in a column becoming NOT NULL")
+	}
+	_, _ = s.Raw("UPDATE Ticket SET Title = 'bug';").Exec()
+	if err := engine.Migrate(&Ticket{}); err != nil {
+		t.Fatal("failed to migrate", err)
+	}
+}
+
+// noInspect 不支持查询表结构的方言
+type noInspect struct {
+	dialect.Dialect
+}
+
+func (noInspect) ColumnsSQL(tableName string) (string, []interface{}) {
+	return "", nil
+}
+
+func TestEngine_MigrateWithoutInspection(t *testing.T) {
+	engine := OpenDB(t)
+	defer engine.Close()
+	engine.dial = noInspect{engine.dial}
+	s := engine.NewSession()
+	_, _ = s.Raw("DROP TABLE IF EXISTS Book;").Exec()
+	_, _ = s.Raw("CREATE TABLE Book (ID integer PRIMARY KEY, Title text);").Exec()
+	plan, err := engine.Plan(&Book{})
+	if err != nil || len(plan.Statements) != 2 || !strings.HasPrefix(plan.Statements[0], `ALTER TABLE "Book" ADD COLUMN "Price"`) {
+		t.Fatal("failed to plan by column names", plan, err)
+	}
+	if err := engine.Apply(plan); err != nil {
+		t.Fatal(err)
+	}
+	if plan, err = engine.Plan(&Book{}); err != nil || len(plan.Statements) != 0 {
+		t.Fatal("expect empty plan after migration, got", plan, err)
+	}
+}
@@ -0,0 +1,39 @@
+package geeorm
+
+import "geeorm/dialect"
+
+// Inspector 数据库结构查看器，用来查询数据库中实际的表、列、索引及外键定义
+// 方言不支持的查询返回 dialect.ErrNotSupported
+type Inspector struct {
+	engine *Engine
+}
+
+// Inspect 返回引擎所连接数据库的结构查看器
+func (e *Engine) Inspect() *Inspector {
+	return &Inspector{engine: e}
+}
+
+// Tables 返回数据库中全部用户表的表名
+func (i *Inspector) Tables() ([]string, error) {
+	return i.engine.NewSession().Tables()
+}
+
+// HasTable 判断数据库中是否存在名为 name 的表
+func (i *Inspector) HasTable(name string) bool {
+	return i.engine.NewSession().TableExists(name)
+}
+
+// Columns 返回表 table 中各列的定义
+func (i *Inspector) Columns(table string) ([]dialect.Column, error) {
+	return i.engine.NewSession().Columns(table)
+}
+
+// Indexes 返回表 table 上的索引，包括由约束隐式创建的索引
+func (i *Inspector) Indexes(table string) ([]dialect.Index, error) {
+	return i.engine.NewSession().Indexes(table)
+}
+
+// ForeignKeys 返回表 table 上的外键
+func (i *Inspector) ForeignKeys(table string) ([]dialect.ForeignKey, error) {
+	return i.engine.NewSession().ForeignKeys(table)
+}
@@ -0,0 +1,50 @@
+package migrate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// fileRegexp SQL迁移文件名格式：<版本号>_<名称>.up.sql 或 <版本号>_<名称>.down.sql
+var fileRegexp = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// FromDir 从目录中加载SQL迁移脚本，同一版本号的 up 与 down 脚本组成一个迁移，不符合命名格式的文件会被忽略
+func FromDir(dir string) ([]*Migration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int64]*Migration)
+	var migrations []*Migration
+	for _, file := range files {
+		match := fileRegexp.FindStringSubmatch(file.Name())
+		if file.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mg
+			migrations = append(migrations, mg)
+		} else if mg.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d has different names %s and %s", version, mg.Name, match[2])
+		}
+		script, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if match[3] == "up" {
+			mg.UpSQL = string(script)
+		} else {
+			mg.DownSQL = string(script)
+		}
+	}
+	return migrations, nil
+}
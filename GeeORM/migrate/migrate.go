@@ -0,0 +1,270 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"geeorm"
+	"geeorm/log"
+	"geeorm/session"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// 迁移记录表与迁移锁表的表名
+const (
+	TableName     = "schema_migrations"
+	LockTableName = "schema_migrations_lock"
+)
+
+// ErrLocked 迁移锁已被其他迁移进程持有
+var ErrLocked = errors.New("geeorm: migrations are locked by another process")
+
+// Migration 一个带版本号的迁移，Up/Down 可以使用Go函数，也可以使用SQL脚本
+// 同时设置时优先使用Go函数，Down 与 DownSQL 都为空的迁移不能回滚
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(s *session.Session) error
+	Down    func(s *session.Session) error
+	UpSQL   string
+	DownSQL string
+}
+
+// Status 一个迁移的执行状态
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Missing   bool // 已经执行但迁移定义已不存在
+}
+
+// Migrator 迁移执行器，负责按版本号顺序执行、回滚迁移，并在 schema_migrations 表中记录执行情况
+type Migrator struct {
+	engine     *geeorm.Engine
+	migrations []*Migration
+}
+
+// New 创建迁移执行器，迁移按版本号排序，版本号不能重复
+func New(engine *geeorm.Engine, migrations ...*Migration) (*Migrator, error) {
+	sorted := make([]*Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, fmt.Errorf("duplicate migration version %d", sorted[i].Version)
+		}
+	}
+	return &Migrator{engine: engine, migrations: sorted}, nil
+}
+
+// Up 执行全部尚未执行的迁移
+func (m *Migrator) Up() error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.MigrateTo(m.migrations[len(m.migrations)-1].Version)
+}
+
+// MigrateTo 迁移到指定版本：执行版本号不大于 version 的未执行迁移，并按倒序回滚版本号大于 version 的已执行迁移
+func (m *Migrator) MigrateTo(version int64) error {
+	return m.withLock(func(applied map[int64]bool) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if mg := m.migrations[i]; mg.Version > version && applied[mg.Version] {
+				if err := m.down(mg); err != nil {
+					return err
+				}
+			}
+		}
+		for _, mg := range m.migrations {
+			if mg.Version <= version && !applied[mg.Version] {
+				if err := m.up(mg); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Rollback 按倒序回滚最近执行的 steps 个迁移
+func (m *Migrator) Rollback(steps int) error {
+	return m.withLock(func(applied map[int64]bool) error {
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			if mg := m.migrations[i]; applied[mg.Version] {
+				if err := m.down(mg); err != nil {
+					return err
+				}
+				steps--
+			}
+		}
+		return nil
+	})
+}
+
+// Status 返回全部迁移的执行状态，按版本号排序
+func (m *Migrator) Status() ([]Status, error) {
+	if err := m.createTables(); err != nil {
+		return nil, err
+	}
+	records, err := m.records()
+	if err != nil {
+		return nil, err
+	}
+	var status []Status
+	known := make(map[int64]bool)
+	for _, mg := range m.migrations {
+		known[mg.Version] = true
+		st := Status{Version: mg.Version, Name: mg.Name}
+		if r, ok := records[mg.Version]; ok {
+			st.Applied, st.AppliedAt = true, r.AppliedAt
+		}
+		status = append(status, st)
+	}
+	for version, r := range records {
+		if !known[version] {
+			status = append(status, Status{Version: version, Name: r.Name, Applied: true, AppliedAt: r.AppliedAt, Missing: true})
+		}
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Version < status[j].Version })
+	return status, nil
+}
+
+// ForceUnlock 强制释放迁移锁，用于迁移进程异常退出后遗留的锁
+func (m *Migrator) ForceUnlock() error {
+	s := m.engine.NewSession()
+	_, err := s.Raw(fmt.Sprintf("DELETE FROM %s", m.quote(LockTableName))).Exec()
+	return err
+}
+
+// up 在事务中执行一个迁移并记录
+func (m *Migrator) up(mg *Migration) error {
+	log.Infof("migrate up %d %s", mg.Version, mg.Name)
+	_, err := m.engine.Transaction(func(s *session.Session) (result interface{}, err error) {
+		if err = run(s, mg.Up, mg.UpSQL); err != nil {
+			return
+		}
+		sql := fmt.Sprintf("INSERT INTO %s (%s, %s, %s) VALUES (?, ?, ?)", m.quote(TableName),
+			m.quote("version"), m.quote("name"), m.quote("applied_at"))
+		return s.Raw(sql, mg.Version, mg.Name, time.Now()).Exec()
+	})
+	if err != nil {
+		return fmt.Errorf("migration %d %s failed: %v", mg.Version, mg.Name, err)
+	}
+	return nil
+}
+
+// down 在事务中回滚一个迁移并删除记录
+func (m *Migrator) down(mg *Migration) error {
+	if mg.Down == nil && mg.DownSQL == "" {
+		return fmt.Errorf("migration %d %s is irreversible", mg.Version, mg.Name)
+	}
+	log.Infof("migrate down %d %s", mg.Version, mg.Name)
+	_, err := m.engine.Transaction(func(s *session.Session) (result interface{}, err error) {
+		if err = run(s, mg.Down, mg.DownSQL); err != nil {
+			return
+		}
+		sql := fmt.Sprintf("DELETE FROM %s WHERE %s = ?", m.quote(TableName), m.quote("version"))
+		return s.Raw(sql, mg.Version).Exec()
+	})
+	if err != nil {
+		return fmt.Errorf("rollback of migration %d %s failed: %v", mg.Version, mg.Name, err)
+	}
+	return nil
+}
+
+// run 执行迁移的Go函数或SQL脚本
+func run(s *session.Session, f func(*session.Session) error, script string) error {
+	if f != nil {
+		return f(s)
+	}
+	if script == "" {
+		return nil
+	}
+	_, err := s.Raw(script).Exec()
+	return err
+}
+
+// withLock 获取迁移锁后执行 f，f 的参数为已执行迁移的版本号集合
+// 迁移锁通过向锁表插入固定主键的记录实现，插入因主键冲突失败说明锁已被其他进程持有
+func (m *Migrator) withLock(f func(applied map[int64]bool) error) error {
+	if err := m.createTables(); err != nil {
+		return err
+	}
+	s := m.engine.NewSession()
+	sql := fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (?, ?)", m.quote(LockTableName), m.quote("id"), m.quote("locked_at"))
+	if _, err := s.Raw(sql, 1, time.Now()).Exec(); err != nil {
+		// 各驱动的主键冲突错误不同，通过锁记录是否存在来判断，其他错误原样返回
+		if m.locked() {
+			return ErrLocked
+		}
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if err := m.ForceUnlock(); err != nil {
+			log.Error(err)
+		}
+	}()
+	records, err := m.records()
+	if err != nil {
+		return err
+	}
+	applied := make(map[int64]bool)
+	for version := range records {
+		applied[version] = true
+	}
+	return f(applied)
+}
+
+// locked 判断锁表中是否存在锁记录
+func (m *Migrator) locked() bool {
+	s := m.engine.NewSession()
+	sql := fmt.Sprintf("SELECT count(*) FROM %s WHERE %s = ?", m.quote(LockTableName), m.quote("id"))
+	var count int
+	return s.Raw(sql, 1).QueryRow().Scan(&count) == nil && count > 0
+}
+
+// createTables 创建迁移记录表与迁移锁表
+func (m *Migrator) createTables() error {
+	d := m.engine.Dialect()
+	typeOf := func(v interface{}) string { return d.DataTypeOf(reflect.ValueOf(v)) }
+	s := m.engine.NewSession()
+	for _, sql := range []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s %s PRIMARY KEY, %s %s, %s %s);", m.quote(TableName),
+			m.quote("version"), typeOf(int64(0)), m.quote("name"), typeOf(""), m.quote("applied_at"), typeOf(time.Time{})),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s %s PRIMARY KEY, %s %s);", m.quote(LockTableName),
+			m.quote("id"), typeOf(0), m.quote("locked_at"), typeOf(time.Time{})),
+	} {
+		if _, err := s.Raw(sql).Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// records 查询迁移记录表中已执行的迁移
+func (m *Migrator) records() (map[int64]Status, error) {
+	s := m.engine.NewSession()
+	rows, err := s.Raw(fmt.Sprintf("SELECT %s, %s, %s FROM %s", m.quote("version"), m.quote("name"),
+		m.quote("applied_at"), m.quote(TableName))).QueryRows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	records := make(map[int64]Status)
+	for rows.Next() {
+		var r Status
+		if err := rows.Scan(&r.Version, &r.Name, &r.AppliedAt); err != nil {
+			return nil, err
+		}
+		r.Applied = true
+		records[r.Version] = r
+	}
+	return records, rows.Err()
+}
+
+// quote 使用引擎的方言为标识符加上引号
+func (m *Migrator) quote(name string) string {
+	return m.engine.Dialect().Quote(name)
+}
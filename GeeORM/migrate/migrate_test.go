@@ -0,0 +1,102 @@
+package migrate
+
+import (
+	"geeorm"
+	"geeorm/session"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type Book struct {
+	Title string `geeorm:"primaryKey"`
+}
+
+func testMigrator(t *testing.T) (*geeorm.Engine, *Migrator) {
+	t.Helper()
+	engine, err := geeorm.NewEngine("sqlite3", "gee.db")
+	if err != nil {
+		t.Fatal("failed to connect to database", err)
+	}
+	s := engine.NewSession()
+	for _, table := range []string{TableName, LockTableName, "Book", "Tag"} {
+		_, _ = s.Raw("DROP TABLE IF EXISTS " + table).Exec()
+	}
+	files, err := FromDir("testdata")
+	if err != nil || len(files) != 1 {
+		t.Fatal("failed to load migrations from dir", err)
+	}
+	migrations := append(files, &Migration{
+		Version: 1,
+		Name:    "create_books",
+		Up:      func(s *session.Session) error { return s.Model(&Book{}).CreateTable() },
+		Down:    func(s *session.Session) error { return s.Model(&Book{}).DropTable() },
+	}, &Migration{
+		Version: 3,
+		Name:    "seed_books",
+		UpSQL:   "INSERT INTO Book (Title) VALUES ('gee')",
+	})
+	m, err := New(engine, migrations...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return engine, m
+}
+
+func TestMigrator(t *testing.T) {
+	engine, m := testMigrator(t)
+	defer engine.Close()
+	if err := m.Up(); err != nil {
+		t.Fatal("failed to migrate up", err)
+	}
+	status, err := m.Status()
+	if err != nil || len(status) != 3 || !status[0].Applied || !status[2].Applied || status[1].Name != "add_tags" {
+		t.Fatal("failed to report status, got", status, err)
+	}
+	if err := m.Rollback(1); err == nil {
+		t.Fatal("expect irreversible migration to fail on rollback")
+	}
+
+	m, _ = New(engine, m.migrations[:2]...)
+	if err := m.MigrateTo(1); err != nil {
+		t.Fatal("failed to migrate down to version 1", err)
+	}
+	status, _ = m.Status()
+	if len(status) != 3 || !status[0].Applied || status[1].Applied || !status[2].Missing {
+		t.Fatal("failed to migrate to version 1, got", status)
+	}
+	if engine.NewSession().Model(&Book{}).HasTable() != true {
+		t.Fatal("expect version 1 to be kept")
+	}
+	if err := m.Rollback(1); err != nil || engine.NewSession().Model(&Book{}).HasTable() {
+		t.Fatal("failed to rollback", err)
+	}
+}
+
+func TestMigrator_Lock(t *testing.T) {
+	engine, m := testMigrator(t)
+	defer engine.Close()
+	_ = m.createTables()
+	_, _ = engine.NewSession().Raw("INSERT INTO " + LockTableName + " (id, locked_at) VALUES (1, 0)").Exec()
+	if err := m.Up(); err != ErrLocked {
+		t.Fatal("expect ErrLocked, but got", err)
+	}
+	if err := m.ForceUnlock(); err != nil || m.Up() != nil {
+		t.Fatal("failed to migrate after unlock", err)
+	}
+
+	// 与锁冲突无关的错误不应被报告为 ErrLocked
+	s := engine.NewSession()
+	_, _ = s.Raw("DROP TABLE " + LockTableName).Exec()
+	_, _ = s.Raw("CREATE TABLE " + LockTableName + " (id integer PRIMARY KEY)").Exec()
+	if err := m.Up(); err == nil || err == ErrLocked {
+		t.Fatal("expect the original error, but got", err)
+	}
+	_, _ = s.Raw("DROP TABLE " + LockTableName).Exec()
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil, &Migration{Version: 1}, &Migration{Version: 1}); err == nil {
+		t.Fatal("expect error of duplicate versions")
+	}
+}
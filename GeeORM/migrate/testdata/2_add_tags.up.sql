@@ -0,0 +1,2 @@
+CREATE TABLE Tag (Name text PRIMARY KEY);
+INSERT INTO Tag (Name) VALUES ('go');
@@ -0,0 +1,293 @@
+package geeorm
+
+import (
+	"fmt"
+	"geeorm/dialect"
+	"geeorm/schema"
+	"geeorm/session"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// MigrationPlan 迁移计划，由将数据库中的表结构变更为模型声明的结构所需的DDL语句按执行顺序组成
+type MigrationPlan struct {
+	Statements []string
+}
+
+// String 返回计划中的全部语句，每行一条
+func (p *MigrationPlan) String() string {
+	var sb strings.Builder
+	for _, stmt := range p.Statements {
+		sb.WriteString(stmt)
+		sb.WriteString(";\n")
+	}
+	return sb.String()
+}
+
+// Plan 比较模型声明的表结构(列、类型、非空、默认值、唯一约束、索引)与数据库中的实际结构，生成迁移计划
+// 生成计划不会修改数据库
+func (e *Engine) Plan(values ...interface{}) (*MigrationPlan, error) {
+	plan := &MigrationPlan{}
+	s := e.NewSession()
+	for _, value := range values {
+		if err := s.Model(value).Err(); err != nil {
+			return nil, err
+		}
+		stmts, err := e.planTable(s)
+		if err != nil {
+			return nil, err
+		}
+		plan.Statements = append(plan.Statements, stmts...)
+	}
+	return plan, nil
+}
+
+// DryRun 生成迁移计划并将其输出到 w，不修改数据库
+func (e *Engine) DryRun(w io.Writer, values ...interface{}) (*MigrationPlan, error) {
+	plan, err := e.Plan(values...)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(w, plan.String())
+	return plan, err
+}
+
+// Apply 在一个事务中依次执行迁移计划中的语句，任意语句失败时整个计划回滚
+func (e *Engine) Apply(plan *MigrationPlan) error {
+	_, err := e.Transaction(func(s *session.Session) (result interface{}, err error) {
+		for _, stmt := range plan.Statements {
+			if _, err = s.Raw(stmt).Exec(); err != nil {
+				return
+			}
+		}
+		return
+	})
+	return err
+}
+
+// Migrate 数据库迁移操作，生成迁移计划并在一个事务中执行
+// 表不存在时建表；新增的列能直接追加时使用 ALTER TABLE ADD COLUMN；
+// 列被删除或类型、约束等发生变化时按照sqlite的方式重建表：以新结构创建临时表，复制两侧共有的列，删除旧表，再将临时表改名
+// 方言不支持查询表结构时只比较列名，追加新增的列并删除多余的列
+func (e *Engine) Migrate(values ...interface{}) error {
+	plan, err := e.Plan(values...)
+	if err != nil {
+		return err
+	}
+	return e.Apply(plan)
+}
+
+// planTable 生成会话当前维护的表的迁移语句
+func (e *Engine) planTable(s *session.Session) ([]string, error) {
+	table := s.GetrefTable()
+	if !s.TableExists(table.Name) {
+		stmts := append([]string{table.CreateTableSQL(e.dial, table.Name)}, createIndexes(e.dial, table)...)
+		return e.planJoinTables(s, stmts)
+	}
+	columns, err := s.Columns(table.Name)
+	if err == dialect.ErrNotSupported {
+		return e.planColumnNames(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := s.Indexes(table.Name)
+	if err != nil {
+		return nil, err
+	}
+	live := make(map[string]dialect.Column)
+	for _, col := range columns {
+		live[col.Name] = col
+	}
+	// 单列 UNIQUE 约束隐式创建的唯一索引用来判断列是否唯一
+	uniques := make(map[string]bool)
+	for _, idx := range indexes {
+		if idx.Implicit && idx.Unique && len(idx.Columns) == 1 && !live[idx.Columns[0]].PrimaryKey {
+			uniques[idx.Columns[0]] = true
+		}
+	}
+
+	var adds []string
+	rebuild := false
+	desired := make(map[string]bool)
+	for _, field := range table.Fields {
+		desired[field.Name] = true
+		col, ok := live[field.Name]
+		switch {
+		case !ok && canAddColumn(field):
+			adds = append(adds, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", e.dial.Quote(table.Name), field.Definition(e.dial)))
+		case !ok || columnChanged(e.dial, field, col, uniques[field.Name]):
+			rebuild = true
+		}
+	}
+	var common []string
+	for _, col := range columns {
+		if !desired[col.Name] {
+			rebuild = true
+			continue
+		}
+		common = append(common, col.Name)
+	}
+
+	if !rebuild {
+		return e.planJoinTables(s, append(adds, diffIndexes(e.dial, table, indexes)...))
+	}
+	tmp := "geeorm_tmp_" + table.Name
+	stmts := []string{table.CreateTableSQL(e.dial, tmp)}
+	if len(common) > 0 {
+		values, err := e.copyValues(s, table, live, common)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", e.dial.Quote(tmp),
+			strings.Join(quoteAll(e.dial, common), ", "), strings.Join(values, ", "), e.dial.Quote(table.Name)))
+	}
+	stmts = append(stmts,
+		fmt.Sprintf("DROP TABLE %s", e.dial.Quote(table.Name)),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", e.dial.Quote(tmp), e.dial.Quote(table.Name)))
+	// 旧表上的索引随旧表一同删除，重建后需要全部重新创建
+	return e.planJoinTables(s, append(stmts, createIndexes(e.dial, table)...))
+}
+
+// copyValues 返回重建表时从旧表复制各列使用的表达式
+// 变为非空的列使用 COALESCE 以默认值替换旧数据中的 NULL，没有默认值且旧数据中存在 NULL 时返回错误
+func (e *Engine) copyValues(s *session.Session, table *schema.Schema, live map[string]dialect.Column, common []string) ([]string, error) {
+	values := make([]string, 0, len(common))
+	for _, name := range common {
+		field, column := table.GetField(name), e.dial.Quote(name)
+		if !field.NotNull || live[name].NotNull {
+			values = append(values, column)
+			continue
+		}
+		if field.HasDefault {
+			values = append(values, fmt.Sprintf("COALESCE(%s, %s)", column, field.Default))
+			continue
+		}
+		var nulls int64
+		row := s.Raw(fmt.Sprintf("SELECT count(*) FROM %s WHERE %s IS NULL", e.dial.Quote(table.Name), column)).QueryRow()
+		if err := row.Scan(&nulls); err != nil {
+			return nil, err
+		}
+		if nulls > 0 {
+			return nil, fmt.Errorf("column %s of %s becomes NOT NULL without a default, but %d rows hold NULL", name, table.Name, nulls)
+		}
+		values = append(values, column)
+	}
+	return values, nil
+}
+
+// planColumnNames 方言不支持查询表结构时，按列名比较模型与数据库中的表，生成追加与删除列的语句
+func (e *Engine) planColumnNames(s *session.Session) ([]string, error) {
+	table := s.GetrefTable()
+	rows, err := s.Raw(fmt.Sprintf("SELECT * FROM %s LIMIT 1", e.dial.Quote(table.Name))).QueryRows()
+	if err != nil {
+		return nil, err
+	}
+	columns, err := rows.Columns()
+	_ = rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	live, desired := make(map[string]bool), make(map[string]bool)
+	for _, col := range columns {
+		live[col] = true
+	}
+	var stmts []string
+	for _, field := range table.Fields {
+		desired[field.Name] = true
+		if !live[field.Name] {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", e.dial.Quote(table.Name), field.Definition(e.dial)))
+		}
+	}
+	for _, col := range columns {
+		if !desired[col] {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", e.dial.Quote(table.Name), e.dial.Quote(col)))
+		}
+	}
+	return e.planJoinTables(s, stmts)
+}
+
+// quoteAll 为一组标识符加上引号
+func quoteAll(d dialect.Dialect, names []string) []string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, d.Quote(name))
+	}
+	return quoted
+}
+
+// planJoinTables 在 stmts 之后追加创建缺失的多对多连接表的语句
+func (e *Engine) planJoinTables(s *session.Session, stmts []string) ([]string, error) {
+	for _, rel := range s.GetrefTable().Relationships {
+		if rel.Kind != schema.ManyToMany || s.TableExists(rel.JoinTable) {
+			continue
+		}
+		sql, err := s.JoinTableSQL(rel)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, sql)
+	}
+	return stmts, nil
+}
+
+// canAddColumn 判断新增的列能否直接通过 ALTER TABLE ADD COLUMN 追加
+// sqlite 不允许追加主键列、唯一列以及没有默认值的非空列
+func canAddColumn(field *schema.Field) bool {
+	return !field.PrimaryKey && !field.Unique && !(field.NotNull && !field.HasDefault)
+}
+
+// columnChanged 判断模型声明的列与数据库中的实际列是否不一致
+func columnChanged(d dialect.Dialect, field *schema.Field, col dialect.Column, unique bool) bool {
+	typ := field.Type
+	if field.AutoIncrement {
+		typ, _ = d.AutoIncrement(typ)
+	}
+	if !strings.EqualFold(typ, col.Type) || field.NotNull != col.NotNull ||
+		field.PrimaryKey != col.PrimaryKey || field.Unique != unique {
+		return true
+	}
+	if col.Default == nil {
+		return field.HasDefault
+	}
+	return !field.HasDefault || field.Default != *col.Default
+}
+
+// createIndexes 生成创建表上全部声明索引的语句
+func createIndexes(d dialect.Dialect, table *schema.Schema) []string {
+	var stmts []string
+	for _, idx := range table.Indexes {
+		stmts = append(stmts, idx.CreateSQL(d, table.Name))
+	}
+	return stmts
+}
+
+// diffIndexes 比较声明的索引与数据库中通过 CREATE INDEX 创建的索引，生成删除多余索引、创建缺失索引的语句
+// 定义发生变化的索引先删除再重新创建
+func diffIndexes(d dialect.Dialect, table *schema.Schema, indexes []dialect.Index) []string {
+	live := make(map[string]dialect.Index)
+	for _, idx := range indexes {
+		if !idx.Implicit {
+			live[idx.Name] = idx
+		}
+	}
+	var drops, creates []string
+	for _, idx := range table.Indexes {
+		old, ok := live[idx.Name]
+		delete(live, idx.Name)
+		if ok && old.Unique == idx.Unique && reflect.DeepEqual(old.Columns, idx.Columns) {
+			continue
+		}
+		if ok {
+			drops = append(drops, "DROP INDEX "+d.Quote(idx.Name))
+		}
+		creates = append(creates, idx.CreateSQL(d, table.Name))
+	}
+	for _, idx := range indexes {
+		if _, ok := live[idx.Name]; ok {
+			drops = append(drops, "DROP INDEX "+d.Quote(idx.Name))
+		}
+	}
+	return append(drops, creates...)
+}
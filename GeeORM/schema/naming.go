@@ -0,0 +1,69 @@
+package schema
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy 命名策略，决定结构体类型与成员变量映射到数据库中的表名与列名
+// 零值保持类型名与成员变量名原样作为表名与列名
+type NamingStrategy struct {
+	TablePrefix  string // 表名前缀
+	SnakeCase    bool   // 是否将类型名与成员变量名转换为蛇形命名，例如 UserProfile 转换为 user_profile
+	PluralTables bool   // 是否使用复数形式的表名，例如 user_profile 转换为 user_profiles
+}
+
+// Tabler 实现了 TableName 方法的模型直接使用其返回值作为表名，不受命名策略影响
+type Tabler interface {
+	TableName() string
+}
+
+// TableName 根据命名策略将结构体类型名转换为表名
+func (ns NamingStrategy) TableName(typeName string) string {
+	name := typeName
+	if ns.SnakeCase {
+		name = toSnakeCase(name)
+	}
+	if ns.PluralTables {
+		name = toPlural(name)
+	}
+	return ns.TablePrefix + name
+}
+
+// ColumnName 根据命名策略将成员变量名转换为列名
+func (ns NamingStrategy) ColumnName(fieldName string) string {
+	if ns.SnakeCase {
+		return toSnakeCase(fieldName)
+	}
+	return fieldName
+}
+
+// toSnakeCase 将驼峰命名转换为蛇形命名，连续的大写字母视为一个缩写词，例如 HTTPServerID 转换为 http_server_id
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// toPlural 将英文单词转换为复数形式，仅处理常见的规则变化
+func toPlural(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsAny(lower[len(lower)-2:len(lower)-1], "aeiou"):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	}
+	return name + "s"
+}
@@ -0,0 +1,133 @@
+package schema
+
+import (
+	"reflect"
+	"time"
+)
+
+// RelationKind 关联关系类型
+type RelationKind int
+
+// 枚举支持的关联关系
+const (
+	HasOne     RelationKind = iota // HasOne 对方持有指向本对象的外键，一对一
+	HasMany                        // HasMany 对方持有指向本对象的外键，一对多
+	BelongsTo                      // BelongsTo 本对象持有指向对方的外键
+	ManyToMany                     // ManyToMany 通过连接表关联，多对多
+)
+
+// Relationship 对象之间的关联关系，外键及引用字段均使用成员变量名记录
+// ManyToMany 时 ForeignKey 为本对象被连接表引用的字段，References 为关联对象被连接表引用的字段
+type Relationship struct {
+	Name           string       // 关联字段的成员变量名
+	Kind           RelationKind // 关联关系类型
+	Type           reflect.Type // 关联对象的结构体类型
+	ForeignKey     string       // 外键字段的成员变量名，BelongsTo 时位于本对象，否则位于关联对象
+	References     string       // 外键所引用字段的成员变量名
+	JoinTable      string       // 连接表名，仅 ManyToMany
+	JoinForeignKey string       // 连接表中引用本对象的列名，仅 ManyToMany
+	JoinReferences string       // 连接表中引用关联对象的列名，仅 ManyToMany
+}
+
+// GetRelationship 根据关联字段的成员变量名获取关联关系
+func (s *Schema) GetRelationship(name string) *Relationship {
+	for _, rel := range s.Relationships {
+		if rel.Name == name {
+			return rel
+		}
+	}
+	return nil
+}
+
+// relationType 判断成员变量是否为关联字段，返回关联对象的结构体类型以及是否为切片
+// 结构体、结构体指针及其切片都视为关联字段，time.Time 及实现了 driver.Valuer 或 sql.Scanner 的类型除外
+func relationType(typ reflect.Type) (reflect.Type, bool, bool) {
+	many := false
+	if typ.Kind() == reflect.Slice {
+		typ, many = typ.Elem(), true
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == reflect.TypeOf(time.Time{}) || isCustomType(typ) {
+		return nil, false, false
+	}
+	return typ, many, true
+}
+
+// primaryFieldName 返回结构体主键字段的成员变量名，未声明主键时约定为 ID
+func primaryFieldName(typ reflect.Type) string {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if _, ok := tagSettings(sf.Tag.Get("geeorm"))["primarykey"]; ok {
+			return sf.Name
+		}
+	}
+	return "ID"
+}
+
+// addRelationship 根据标签及命名约定解析关联关系
+// 标签 foreignKey 指定外键字段，references 指定被引用字段
+// 结构体字段在本对象存在外键字段(默认为 字段名+ID)时视为 BelongsTo，否则为 HasOne
+// 切片字段视为 HasMany，外键默认为关联对象中的 本对象类型名+ID
+// 切片字段声明了 many2many:连接表名 时视为 ManyToMany，连接表的列名默认为 类型名+主键名
+// 可以通过 joinForeignKey 与 joinReferences 指定，默认的列名遵循命名策略
+func (s *Schema) addRelationship(modelType reflect.Type, sf reflect.StructField, relType reflect.Type, many bool, tag string, naming NamingStrategy) {
+	settings := tagSettings(tag)
+	rel := &Relationship{
+		Name:       sf.Name,
+		Type:       relType,
+		ForeignKey: settings["foreignkey"],
+		References: settings["references"],
+	}
+	if joinTable, ok := settings["many2many"]; ok && many {
+		rel.Kind = ManyToMany
+		rel.JoinTable = joinTable
+		if rel.ForeignKey == "" {
+			rel.ForeignKey = primaryFieldName(modelType)
+		}
+		if rel.References == "" {
+			rel.References = primaryFieldName(relType)
+		}
+		rel.JoinForeignKey = settings["joinforeignkey"]
+		if rel.JoinForeignKey == "" {
+			rel.JoinForeignKey = naming.ColumnName(modelType.Name() + rel.ForeignKey)
+		}
+		rel.JoinReferences = settings["joinreferences"]
+		if rel.JoinReferences == "" {
+			rel.JoinReferences = naming.ColumnName(relType.Name() + rel.References)
+		}
+		s.Relationships = append(s.Relationships, rel)
+		return
+	}
+	belongsTo := false
+	if !many {
+		fk := rel.ForeignKey
+		if fk == "" {
+			fk = sf.Name + "ID"
+		}
+		_, belongsTo = modelType.FieldByName(fk)
+	}
+	switch {
+	case belongsTo:
+		rel.Kind = BelongsTo
+		if rel.ForeignKey == "" {
+			rel.ForeignKey = sf.Name + "ID"
+		}
+		if rel.References == "" {
+			rel.References = primaryFieldName(relType)
+		}
+	default:
+		rel.Kind = HasOne
+		if many {
+			rel.Kind = HasMany
+		}
+		if rel.ForeignKey == "" {
+			rel.ForeignKey = modelType.Name() + "ID"
+		}
+		if rel.References == "" {
+			rel.References = primaryFieldName(modelType)
+		}
+	}
+	s.Relationships = append(s.Relationships, rel)
+}
@@ -1,28 +1,68 @@
 package schema
 
 import (
+	"fmt"
 	"geeorm/dialect"
 	"geeorm/log"
 	"go/ast"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Field 表字段类型，用来映射一个成员变量与数据库中的一个字段
 type Field struct {
-	Name string
-	Type string
-	Tag string
+	Name          string // 数据库中的列名
+	GoName        string // 对象中对应的成员变量名
+	Type          string // 数据库中的列类型
+	Tag           string // 原始的geeorm标签
+	Size          int    // 列长度，仅对字符串类型生效
+	NotNull       bool   // 是否非空
+	Default       string // 默认值，原样写入建表语句
+	HasDefault    bool   // 是否设置了默认值
+	Unique        bool   // 是否唯一
+	PrimaryKey    bool   // 是否为主键
+	AutoIncrement bool   // 是否自增
+	SoftDelete    bool   // 是否为软删除标记列
+	Version       bool   // 是否为乐观锁版本号列
+	// AutoCreateTime 插入时自动写入当前时间，AutoUpdateTime 插入及更新时自动写入当前时间
+	AutoCreateTime bool
+	AutoUpdateTime bool
+	// TimeUnit 整数类型时间戳列的精度，默认为秒
+	TimeUnit time.Duration
+	// Serializer 写入与读取时对成员变量编码、解码使用的序列化器名称，例如 json、gob
+	Serializer string
 }
 
 // Schema 表概要类型，用来维护一个对象与一张数据库中的表之间的映射关系，存储表中相关数据
 type Schema struct {
-	Model interface{}
-	Name string
-	Fields []*Field
-	FieldNames []string
+	Model         interface{}
+	Name          string
+	Fields        []*Field
+	FieldNames    []string
+	PrimaryFields []*Field
+	Relationships []*Relationship
+	// SoftDeleteField 软删除标记列，删除时写入删除时间，为 NULL 的行才是有效记录
+	SoftDeleteField *Field
+	// VersionField 乐观锁版本号列，更新时校验并自增
+	VersionField *Field
+	// AutoIncrementField 自增列，插入后将数据库生成的值写回对象
+	AutoIncrementField *Field
+	// Indexes 通过 index、uniqueIndex 标签声明的索引，同名的索引由多个字段按声明顺序组成联合索引
+	Indexes  []*Index
 	fieldMap map[string]*Field
 }
 
+// Index 表上的索引
+type Index struct {
+	Name    string
+	Unique  bool
+	Columns []string
+	// implicitName 为 true 时索引名由表名生成，在其他表名上建立索引时随表名变化
+	implicitName bool
+}
+
 // GetField 根据字段名称获取对应字段
 func (s *Schema) GetField(name string) *Field{
 	field, ok := s.fieldMap[name]
@@ -32,39 +72,342 @@ func (s *Schema) GetField(name string) *Field{
 	return field
 }
 
+// LookUpField 根据成员变量名获取对应字段
+func (s *Schema) LookUpField(goName string) *Field {
+	for _, field := range s.Fields {
+		if field.GoName == goName {
+			return field
+		}
+	}
+	return nil
+}
+
 // RecordValues 将一个类对象根据成员变量顺序，平铺其对应的值，返回的是各个成员的值切片
 func (s *Schema) RecordValues(dest interface{}) []interface{} {
 	destValue := reflect.Indirect(reflect.ValueOf(dest))
 	var fieldValues []interface{}
 	for _, field := range s.Fields {
-		fieldValues = append(fieldValues, destValue.FieldByName(field.Name).Interface())
+		fieldValues = append(fieldValues, field.ValueOf(destValue.FieldByName(field.GoName)))
 	}
 	return fieldValues
 }
 
-// Parse 用来将一个对象映射成一个表概要
-func Parse(obj interface{}, d dialect.Dialect) *Schema {
+// Definition 根据字段属性生成建表语句中的列定义
+func (f *Field) Definition(d dialect.Dialect) string {
+	return f.definition(d, f.PrimaryKey)
+}
+
+// definition 生成列定义，inlinePK 为 false 时主键约束交由表级约束声明
+func (f *Field) definition(d dialect.Dialect, inlinePK bool) string {
+	typ, keyword := f.Type, ""
+	if f.AutoIncrement {
+		typ, keyword = d.AutoIncrement(typ)
+	}
+	parts := []string{d.Quote(f.Name), typ}
+	if inlinePK {
+		parts = append(parts, "PRIMARY KEY")
+	}
+	if keyword != "" {
+		parts = append(parts, keyword)
+	}
+	if f.NotNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if f.Unique {
+		parts = append(parts, "UNIQUE")
+	}
+	if f.HasDefault {
+		parts = append(parts, "DEFAULT "+f.Default)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Definitions 生成建表语句中全部的列定义，联合主键以表级约束的形式追加在末尾
+func (s *Schema) Definitions(d dialect.Dialect) []string {
+	var defs []string
+	inlinePK := len(s.PrimaryFields) == 1
+	for _, field := range s.Fields {
+		defs = append(defs, field.definition(d, inlinePK && field.PrimaryKey))
+	}
+	if len(s.PrimaryFields) > 1 {
+		var keys []string
+		for _, field := range s.PrimaryFields {
+			keys = append(keys, d.Quote(field.Name))
+		}
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(keys, ", ")))
+	}
+	return defs
+}
+
+// CreateTableSQL 生成以 name 为表名的建表语句
+func (s *Schema) CreateTableSQL(d dialect.Dialect, name string) string {
+	return fmt.Sprintf("CREATE TABLE %s (%s)", d.Quote(name), strings.Join(s.Definitions(d), ","))
+}
+
+// CreateSQL 生成在表 table 上创建索引的语句
+func (idx *Index) CreateSQL(d dialect.Dialect, table string) string {
+	var cols []string
+	for _, col := range idx.Columns {
+		cols = append(cols, d.Quote(col))
+	}
+	unique := ""
+	if idx.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)", unique, d.Quote(idx.NameOn(table)), d.Quote(table), strings.Join(cols, ", "))
+}
+
+// NameOn 返回索引建立在表 table 上时的索引名，未显式命名的索引名为 idx_表名_列名
+func (idx *Index) NameOn(table string) string {
+	if idx.implicitName {
+		return "idx_" + table + "_" + idx.Columns[0]
+	}
+	return idx.Name
+}
+
+// addIndex 将字段加入名为 name 的索引，name 为空时使用 idx_表名_列名
+func (s *Schema) addIndex(name string, unique bool, field *Field) {
+	implicit := name == ""
+	if implicit {
+		name = "idx_" + s.Name + "_" + field.Name
+	}
+	for _, idx := range s.Indexes {
+		if idx.Name == name {
+			idx.Columns = append(idx.Columns, field.Name)
+			idx.Unique = idx.Unique || unique
+			return
+		}
+	}
+	s.Indexes = append(s.Indexes, &Index{Name: name, Unique: unique, Columns: []string{field.Name}, implicitName: implicit})
+}
+
+// tagSettings 解析geeorm标签，标签由分号分隔，每一项为 key 或 key:value 的形式
+// 例如 `geeorm:"column:user_name;type:varchar(64);not null;default:0;unique;index:idx_user_name"`
+// 键名大小写、空格及下划线不敏感，因此 "PRIMARY KEY" 与 "primaryKey" 等价
+func tagSettings(tag string) map[string]string {
+	settings := make(map[string]string)
+	for _, setting := range orderedTagSettings(tag) {
+		settings[setting.key] = setting.value
+	}
+	return settings
+}
+
+// tagSetting 标签中的一项设置
+type tagSetting struct {
+	key   string
+	value string
+}
+
+// orderedTagSettings 按标签中出现的顺序解析各项设置，键名的规则与 tagSettings 相同
+func orderedTagSettings(tag string) []tagSetting {
+	var settings []tagSetting
+	for _, item := range strings.Split(tag, ";") {
+		kv := strings.SplitN(item, ":", 2)
+		key := strings.ToLower(strings.NewReplacer(" ", "", "_", "").Replace(kv[0]))
+		if key == "" {
+			continue
+		}
+		setting := tagSetting{key: key}
+		if len(kv) == 2 {
+			setting.value = strings.TrimSpace(kv[1])
+		}
+		settings = append(settings, setting)
+	}
+	return settings
+}
+
+// parseTag 按标签中出现的顺序设置字段属性，遇到无效的设置时返回错误
+func parseTag(field *Field, tag string) error {
+	for _, setting := range orderedTagSettings(tag) {
+		key, value := setting.key, setting.value
+		switch key {
+		case "column":
+			field.Name = value
+		case "type":
+			field.Type = value
+		case "size":
+			size, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid size %q of field %s", value, field.GoName)
+			}
+			field.Size = size
+		case "notnull":
+			field.NotNull = true
+		case "default":
+			field.Default, field.HasDefault = value, true
+		case "unique":
+			field.Unique = true
+		case "primarykey":
+			field.PrimaryKey = true
+		case "autoincrement":
+			field.AutoIncrement = true
+		case "softdelete":
+			field.SoftDelete = true
+		case "version":
+			field.Version = true
+		case "serializer":
+			if _, ok := serializers[value]; !ok {
+				return fmt.Errorf("unknown serializer %q of field %s", value, field.GoName)
+			}
+			field.Serializer = value
+		case "index", "uniqueindex":
+			// 索引属于表，由 Parse 统一收集
+		case "autocreatetime", "autoupdatetime":
+			field.AutoCreateTime = field.AutoCreateTime || key == "autocreatetime"
+			field.AutoUpdateTime = field.AutoUpdateTime || key == "autoupdatetime"
+			switch value {
+			case "", "second":
+			case "milli":
+				field.TimeUnit = time.Millisecond
+			case "nano":
+				field.TimeUnit = time.Nanosecond
+			default:
+				return fmt.Errorf("invalid time unit %q of field %s", value, field.GoName)
+			}
+		default:
+			return fmt.Errorf("unknown tag %q of field %s", key, field.GoName)
+		}
+	}
+	return nil
+}
+
+// isTimestampType 判断类型能否存储时间戳，time.Time 存储时间，整数存储 Unix 时间戳
+func isTimestampType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return typ == reflect.TypeOf(time.Time{})
+}
+
+// Parse 用来将一个对象映射成一个表概要，类型名与成员变量名原样作为表名与列名
+func Parse(obj interface{}, d dialect.Dialect) (*Schema, error) {
+	return ParseWithNaming(obj, d, NamingStrategy{})
+}
+
+// ParseWithNaming 使用给定的命名策略将一个对象映射成一个表概要
+// 对象实现了 Tabler 时使用 TableName 的返回值作为表名，标签中的 column 优先于命名策略
+// 标签中存在无效的设置时返回错误
+func ParseWithNaming(obj interface{}, d dialect.Dialect, naming NamingStrategy) (*Schema, error) {
 	modelType := reflect.Indirect(reflect.ValueOf(obj)).Type()
 	s := &Schema{
 		Model: obj,
-		Name: modelType.Name(),
+		Name: naming.TableName(modelType.Name()),
 		fieldMap: make(map[string]*Field),
 	}
+	if tabler, ok := reflect.New(modelType).Interface().(Tabler); ok {
+		s.Name = tabler.TableName()
+	}
+	// 遍历对象的每一个成员，将其映射成表中的字段
 	for i := 0; i < modelType.NumField(); i++ {
 		sf := modelType.Field(i)
-		if !sf.Anonymous && ast.IsExported(sf.Name){
-			field := &Field{
-				Name: sf.Name,
-				Type: sf.Type.Name(),
+		if sf.Anonymous || !ast.IsExported(sf.Name) {
+			continue
+		}
+		tag, _ := sf.Tag.Lookup("geeorm")
+		if tag == "-" {
+			continue
+		}
+		// 声明了序列化器的成员变量编码后存为一列，不作为关联字段
+		_, serialized := tagSettings(tag)["serializer"]
+		if relType, many, ok := relationType(sf.Type); ok && !serialized {
+			s.addRelationship(modelType, sf, relType, many, tag, naming)
+			continue
+		}
+		field := &Field{
+			Name:   naming.ColumnName(sf.Name),
+			GoName: sf.Name,
+			Tag:    tag,
+		}
+		if err := parseTag(field, tag); err != nil {
+			return nil, err
+		}
+		// 指针类型的成员变量映射为可为 NULL 的列，列类型由其指向的类型决定
+		fieldType := sf.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		// 名为 DeletedAt 的 *time.Time 成员变量约定为软删除标记列
+		if sf.Name == "DeletedAt" && sf.Type == reflect.TypeOf(&time.Time{}) {
+			field.SoftDelete = true
+		}
+		// 名为 CreatedAt、UpdatedAt 的时间或整数成员变量约定为自动维护的时间戳
+		// 整数时间戳不支持指针，*time.Time 可以
+		timestamp := isTimestampType(sf.Type) || sf.Type == reflect.TypeOf(&time.Time{})
+		if timestamp {
+			field.AutoCreateTime = field.AutoCreateTime || sf.Name == "CreatedAt"
+			field.AutoUpdateTime = field.AutoUpdateTime || sf.Name == "UpdatedAt"
+		} else if field.AutoCreateTime || field.AutoUpdateTime {
+			return nil, fmt.Errorf("field %s (%s) cannot store an auto timestamp, use time.Time or an integer type", sf.Name, sf.Type)
+		}
+		if field.TimeUnit == 0 {
+			field.TimeUnit = time.Second
+		}
+		if dataTyper, ok := reflect.New(fieldType).Interface().(DataTyper); ok && field.Type == "" {
+			field.Type = dataTyper.GeeormDataType()
+		}
+		if field.Serializer == "json" && field.Type == "" {
+			field.Type = d.DataTypeOf(reflect.ValueOf(""))
+		} else if field.Serializer != "" && field.Type == "" {
+			field.Type = d.DataTypeOf(reflect.ValueOf([]byte{}))
+		}
+		if field.Type == "" && fieldType.Kind() == reflect.Struct && isCustomType(fieldType) && !isValuer(fieldType) {
+			// 只实现了 sql.Scanner 的结构体无法推断写入的值，也就无法推断列类型
+			return nil, fmt.Errorf("cannot infer column type of field %s (%s), implement driver.Valuer or DataTyper, or declare the type tag", sf.Name, fieldType)
+		}
+		if field.Type == "" {
+			field.Type = d.DataTypeOf(reflect.Indirect(reflect.New(fieldType)))
+			if field.Size > 0 && fieldType.Kind() == reflect.String {
+				field.Type = fmt.Sprintf("varchar(%d)", field.Size)
 			}
-			if tag, ok := sf.Tag.Lookup("geeorm"); ok {
-				field.Tag = tag
+		}
+		s.Fields = append(s.Fields, field)
+		s.FieldNames = append(s.FieldNames, field.Name)
+		s.fieldMap[field.Name] = field
+		if field.PrimaryKey {
+			s.PrimaryFields = append(s.PrimaryFields, field)
+		}
+		if field.SoftDelete {
+			s.SoftDeleteField = field
+		}
+		if field.Version {
+			s.VersionField = field
+		}
+		if field.AutoIncrement {
+			s.AutoIncrementField = field
+		}
+		settings := tagSettings(tag)
+		for _, key := range []string{"index", "uniqueindex"} {
+			if name, ok := settings[key]; ok {
+				s.addIndex(name, key == "uniqueindex", field)
 			}
-			s.Fields = append(s.Fields, field)
-			s.FieldNames = append(s.FieldNames, field.Name)
-			s.fieldMap[field.Name] = field
 		}
 	}
-	// 遍历对象的每一个成员，将其映射成表中的字段
-	return s
+	return s, nil
+}
+
+// ScanFields 解析结构体中可以接收查询结果的字段，用于将结果写入任意结构体
+// 只确定列名与序列化器，不推断列类型，因此成员变量可以是 interface{}、map 等任意类型
+func ScanFields(typ reflect.Type, naming NamingStrategy) []*Field {
+	var fields []*Field
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.Anonymous || !ast.IsExported(sf.Name) {
+			continue
+		}
+		tag, _ := sf.Tag.Lookup("geeorm")
+		if tag == "-" {
+			continue
+		}
+		settings := tagSettings(tag)
+		field := &Field{Name: naming.ColumnName(sf.Name), GoName: sf.Name, Tag: tag}
+		if column, ok := settings["column"]; ok {
+			field.Name = column
+		}
+		if _, ok := serializers[settings["serializer"]]; ok {
+			field.Serializer = settings["serializer"]
+		}
+		fields = append(fields, field)
+	}
+	return fields
 }
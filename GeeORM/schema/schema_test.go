@@ -1,7 +1,10 @@
 package schema
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"geeorm/dialect"
+	"reflect"
 	"testing"
 )
 
@@ -13,11 +16,215 @@ type User struct {
 func TestParse(t *testing.T) {
 	user := User{Name: "Jack", Age: 10}
 	dial, _ := dialect.GetDialect("sqlite3")
-	s := Parse(user, dial) 
+	s, _ := Parse(user, dial) 
 	if len(s.FieldNames) != 2 || s.Name != "User" {
 		t.Fatalf("failed to parse User struct")
 	}
 	if s.GetField("Name").Tag != "PRIMARY KEY" {
 		t.Fatalf("Parse primary key failed")
 	}
-}
\ No newline at end of file
+}
+
+type Product struct {
+	ID     int64   `geeorm:"primaryKey;autoIncrement"`
+	Title  string  `geeorm:"column:title;size:64;not null;unique"`
+	Price  float64 `geeorm:"type:decimal(10,2);default:0"`
+	Ignore string  `geeorm:"-"`
+}
+
+func TestParseTag(t *testing.T) {
+	dial, _ := dialect.GetDialect("sqlite3")
+	s, _ := Parse(&Product{}, dial)
+	if len(s.Fields) != 3 || len(s.PrimaryFields) != 1 {
+		t.Fatalf("failed to parse Product struct, got %v", s.FieldNames)
+	}
+	title := s.GetField("title")
+	if title == nil || title.GoName != "Title" || title.Type != "varchar(64)" || !title.NotNull || !title.Unique {
+		t.Fatalf("failed to parse column tag, got %+v", title)
+	}
+	defs := s.Definitions(dial)
+	expect := []string{
+		`"ID" integer PRIMARY KEY AUTOINCREMENT`,
+		`"title" varchar(64) NOT NULL UNIQUE`,
+		`"Price" decimal(10,2) DEFAULT 0`,
+	}
+	for i := range expect {
+		if defs[i] != expect[i] {
+			t.Fatalf("expect %q, but got %q", expect[i], defs[i])
+		}
+	}
+}
+
+type BadTag struct {
+	ID   int    `geeorm:"primaryKey;size:big;unknown"`
+	Name string `geeorm:"autoCreateTime:hour"`
+}
+
+func TestParseInvalidTag(t *testing.T) {
+	dial, _ := dialect.GetDialect("sqlite3")
+	for i := 0; i < 10; i++ {
+		if _, err := Parse(&BadTag{}, dial); err == nil || err.Error() != `invalid size "big" of field ID` {
+			t.Fatal("expect the first invalid setting to be reported, got", err)
+		}
+	}
+}
+
+type BadTimestamp struct {
+	ID      int    `geeorm:"primaryKey"`
+	Created string `geeorm:"autoCreateTime"`
+}
+
+func TestParseTimestampType(t *testing.T) {
+	dial, _ := dialect.GetDialect("sqlite3")
+	if _, err := Parse(&BadTimestamp{}, dial); err == nil {
+		t.Fatal("expect error for auto timestamp on a string field")
+	}
+}
+
+type Company struct {
+	ID    int `geeorm:"primaryKey"`
+	Staff []Staff
+}
+
+type Staff struct {
+	Name      string `geeorm:"primaryKey"`
+	CompanyID int
+	Company   *Company
+}
+
+func TestParseRelationship(t *testing.T) {
+	dial, _ := dialect.GetDialect("sqlite3")
+	company, _ := Parse(&Company{}, dial)
+	staff, _ := Parse(&Staff{}, dial)
+	if len(company.Fields) != 1 || len(staff.Fields) != 2 {
+		t.Fatal("relationship fields should not be columns")
+	}
+	rel := company.GetRelationship("Staff")
+	if rel == nil || rel.Kind != HasMany || rel.ForeignKey != "CompanyID" || rel.References != "ID" {
+		t.Fatalf("failed to parse has many relationship, got %+v", rel)
+	}
+	rel = staff.GetRelationship("Company")
+	if rel == nil || rel.Kind != BelongsTo || rel.ForeignKey != "CompanyID" || rel.References != "ID" {
+		t.Fatalf("failed to parse belongs to relationship, got %+v", rel)
+	}
+}
+
+type Account struct {
+	ID    int    `geeorm:"primaryKey"`
+	Email string `geeorm:"uniqueIndex"`
+	First string `geeorm:"index:idx_account_name"`
+	Last  string `geeorm:"index:idx_account_name"`
+}
+
+func TestParseIndex(t *testing.T) {
+	dial, _ := dialect.GetDialect("sqlite3")
+	s, _ := Parse(&Account{}, dial)
+	if len(s.Indexes) != 2 {
+		t.Fatalf("expect 2 indexes, but got %d", len(s.Indexes))
+	}
+	expect := []string{
+		`CREATE UNIQUE INDEX "idx_Account_Email" ON "Account" ("Email")`,
+		`CREATE INDEX "idx_account_name" ON "Account" ("First", "Last")`,
+	}
+	for i, idx := range s.Indexes {
+		if sql := idx.CreateSQL(dial, s.Name); sql != expect[i] {
+			t.Fatalf("expect %q, but got %q", expect[i], sql)
+		}
+	}
+}
+
+func TestNamingStrategy(t *testing.T) {
+	naming := NamingStrategy{SnakeCase: true, PluralTables: true}
+	cases := map[string]string{
+		"UserProfile": "user_profiles",
+		"Category":    "categories",
+		"Box":         "boxes",
+		"HTTPServer":  "http_servers",
+		"Day":         "days",
+	}
+	for typeName, expect := range cases {
+		if name := naming.TableName(typeName); name != expect {
+			t.Fatalf("expect %q, but got %q", expect, name)
+		}
+	}
+	if name := naming.ColumnName("OwnerID"); name != "owner_id" {
+		t.Fatalf("expect owner_id, but got %q", name)
+	}
+	if name := (NamingStrategy{}).TableName("UserProfile"); name != "UserProfile" {
+		t.Fatalf("expect verbatim name, but got %q", name)
+	}
+}
+
+type Money struct {
+	Cents int64
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.Cents, nil
+}
+
+func (m *Money) Scan(src interface{}) error {
+	m.Cents, _ = src.(int64)
+	return nil
+}
+
+type UUID [16]byte
+
+func (UUID) GeeormDataType() string {
+	return "char(36)"
+}
+
+type Order struct {
+	ID       int
+	Price    Money
+	Discount sql.NullInt64
+	Ref      UUID
+	Attrs    map[string]string `geeorm:"serializer:json"`
+	Detail   Money             `geeorm:"serializer:gob"`
+}
+
+func TestParseCustomType(t *testing.T) {
+	dial, _ := dialect.GetDialect("sqlite3")
+	s, _ := Parse(&Order{}, dial)
+	if len(s.Relationships) != 0 {
+		t.Fatal("Valuer/Scanner types should not be parsed as relationships")
+	}
+	expect := map[string]string{"Price": "bigint", "Discount": "bigint", "Ref": "char(36)", "Attrs": "text", "Detail": "blob"}
+	for name, typ := range expect {
+		if field := s.GetField(name); field == nil || field.Type != typ {
+			t.Fatalf("expect %s to be %s, but got %v", name, typ, field)
+		}
+	}
+	if s.GetField("Attrs").Serializer != "json" || s.GetField("Detail").Serializer != "gob" {
+		t.Fatal("failed to parse serializer")
+	}
+}
+
+type Cents string
+
+func (c Cents) Value() (driver.Value, error) {
+	return int64(len(c)), nil
+}
+
+type Point struct {
+	X, Y int
+}
+
+func (p *Point) Scan(src interface{}) error {
+	return nil
+}
+
+type Shape struct {
+	Price  Cents
+	Center Point
+}
+
+func TestParseNonStructValuer(t *testing.T) {
+	dial, _ := dialect.GetDialect("sqlite3")
+	if typ := dial.DataTypeOf(reflect.ValueOf(Cents(""))); typ != "bigint" {
+		t.Fatal("expect Value() of a non-struct Valuer to decide the type, got", typ)
+	}
+	if _, err := Parse(&Shape{}, dial); err == nil {
+		t.Fatal("expect error for Scanner-only struct without column type")
+	}
+}
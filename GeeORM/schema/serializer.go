@@ -0,0 +1,142 @@
+package schema
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// DataTyper 实现了 GeeormDataType 的成员变量类型使用其返回值作为列类型，优先级低于标签中的 type
+type DataTyper interface {
+	GeeormDataType() string
+}
+
+// Serializer 序列化器，通过标签 serializer:名称 启用，写入数据库前编码成员变量，读取后解码
+type Serializer interface {
+	// Serialize 将成员变量的值编码为写入数据库的值
+	Serialize(v interface{}) (driver.Value, error)
+	// Deserialize 将数据库中读取的值解码到 dest 指向的成员变量，src 为 nil 时不会被调用
+	Deserialize(src interface{}, dest interface{}) error
+}
+
+var serializers = map[string]Serializer{
+	"json": jsonSerializer{},
+	"gob":  gobSerializer{},
+}
+
+// RegisterSerializer 注册自定义的序列化器
+func RegisterSerializer(name string, s Serializer) {
+	serializers[name] = s
+}
+
+// jsonSerializer 将成员变量编码为JSON字符串
+type jsonSerializer struct{}
+
+// Serialize 编码为JSON字符串
+func (jsonSerializer) Serialize(v interface{}) (driver.Value, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// Deserialize 从JSON字符串或字节切片解码
+func (jsonSerializer) Deserialize(src interface{}, dest interface{}) error {
+	data, err := srcBytes(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// gobSerializer 将成员变量编码为gob字节切片
+type gobSerializer struct{}
+
+// Serialize 编码为gob字节切片
+func (gobSerializer) Serialize(v interface{}) (driver.Value, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Deserialize 从gob字节切片解码
+func (gobSerializer) Deserialize(src interface{}, dest interface{}) error {
+	data, err := srcBytes(src)
+	if err != nil {
+		return err
+	}
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(dest)
+}
+
+// srcBytes 将数据库中读取的字符串或字节切片转换为字节切片
+func srcBytes(src interface{}) ([]byte, error) {
+	switch v := src.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	return nil, fmt.Errorf("cannot deserialize %T", src)
+}
+
+// serialized 写入数据库时才对成员变量编码，编码错误由执行语句返回
+type serialized struct {
+	serializer Serializer
+	value      interface{}
+}
+
+// Value 实现 driver.Valuer
+func (s serialized) Value() (driver.Value, error) {
+	return s.serializer.Serialize(s.value)
+}
+
+// deserializer 读取数据库时解码到成员变量，NULL 写入零值
+type deserializer struct {
+	serializer Serializer
+	dest       reflect.Value
+}
+
+// Scan 实现 sql.Scanner
+func (d deserializer) Scan(src interface{}) error {
+	if src == nil {
+		d.dest.Set(reflect.Zero(d.dest.Type()))
+		return nil
+	}
+	return d.serializer.Deserialize(src, d.dest.Addr().Interface())
+}
+
+// ValueOf 返回成员变量写入数据库的值，声明了序列化器的字段在写入时编码
+func (f *Field) ValueOf(v reflect.Value) interface{} {
+	if s, ok := serializers[f.Serializer]; ok {
+		return serialized{s, v.Interface()}
+	}
+	return v.Interface()
+}
+
+// ScanTarget 返回读取该列时传给 Scan 的目标，声明了序列化器的字段在读取时解码
+// v 为可寻址的成员变量
+func (f *Field) ScanTarget(v reflect.Value) interface{} {
+	if s, ok := serializers[f.Serializer]; ok {
+		return deserializer{s, v}
+	}
+	return v.Addr().Interface()
+}
+
+var (
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// isCustomType 判断类型是否自行实现了与数据库之间的转换，这样的结构体作为普通的列而不是关联对象
+func isCustomType(typ reflect.Type) bool {
+	return isValuer(typ) || reflect.PtrTo(typ).Implements(scannerType)
+}
+
+// isValuer 判断类型或其指针是否实现了 driver.Valuer
+func isValuer(typ reflect.Type) bool {
+	return typ.Implements(valuerType) || reflect.PtrTo(typ).Implements(valuerType)
+}
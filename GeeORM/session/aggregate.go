@@ -0,0 +1,34 @@
+package session
+
+import "geeorm/clause"
+
+// Sum 计算列的总和并写入 dest，dest 为整数、浮点数等标量的指针，没有符合条件的行时结果为 NULL
+func (s *Session) Sum(column string, dest interface{}) error {
+	return s.aggregate("SUM", column, dest)
+}
+
+// Avg 计算列的平均值并写入 dest
+func (s *Session) Avg(column string, dest interface{}) error {
+	return s.aggregate("AVG", column, dest)
+}
+
+// Min 计算列的最小值并写入 dest
+func (s *Session) Min(column string, dest interface{}) error {
+	return s.aggregate("MIN", column, dest)
+}
+
+// Max 计算列的最大值并写入 dest
+func (s *Session) Max(column string, dest interface{}) error {
+	return s.aggregate("MAX", column, dest)
+}
+
+// aggregate 执行聚合查询，查询条件与软删除规则与 Find 相同，设置了 Distinct 时只统计不重复的值
+func (s *Session) aggregate(fn string, column string, dest interface{}) error {
+	if err := s.modelErr(); err != nil {
+		return err
+	}
+	s.softDeleteScope()
+	s.clause.Set(clause.AGGREGATE, s.tableName(), fn, column, s.distinct)
+	sql, vars := s.clause.Build(clause.AGGREGATE, clause.WHERE)
+	return s.Raw(sql, vars...).QueryRow().Scan(dest)
+}
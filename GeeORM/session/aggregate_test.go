@@ -0,0 +1,70 @@
+package session
+
+import "testing"
+
+type Sale struct {
+	ID     int `geeorm:"primaryKey"`
+	Region string
+	Amount float64
+}
+
+func prepareSales(t *testing.T) *Session {
+	t.Helper()
+	s := NewSession().Model(&Sale{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	_, err := s.Insert(
+		&Sale{1, "east", 10}, &Sale{2, "east", 20}, &Sale{3, "west", 5},
+		&Sale{4, "west", 15}, &Sale{5, "north", 40},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSession_Aggregate(t *testing.T) {
+	s := prepareSales(t)
+	var sum, avg float64
+	var min, max string
+	if err := s.Sum("Amount", &sum); err != nil || sum != 90 {
+		t.Fatal("expect sum 90, but got", sum, err)
+	}
+	if err := s.Where("Region = ?", "east").Avg("Amount", &avg); err != nil || avg != 15 {
+		t.Fatal("expect avg 15, but got", avg, err)
+	}
+	if err := s.Min("Region", &min); err != nil || min != "east" {
+		t.Fatal("expect min east, but got", min, err)
+	}
+	if err := s.Max("Region", &max); err != nil || max != "west" {
+		t.Fatal("expect max west, but got", max, err)
+	}
+	if n, err := s.Distinct().Count("Region"); err != nil || n != 3 {
+		t.Fatal("expect 3 distinct regions, but got", n, err)
+	}
+}
+
+func TestSession_Select(t *testing.T) {
+	s := prepareSales(t)
+	var sales []Sale
+	if err := s.Select("Region").Distinct().Orderby("Region").Find(&sales); err != nil || len(sales) != 3 ||
+		sales[0].Region != "east" || sales[0].ID != 0 {
+		t.Fatal("failed to select distinct regions", sales, err)
+	}
+	sales = nil
+	if err := s.Orderby("ID").Limit(2).Offset(1).Find(&sales); err != nil || len(sales) != 2 || sales[0].ID != 2 {
+		t.Fatal("failed to query with offset", sales, err)
+	}
+	sales = nil
+	if err := s.Orderby("ID").Offset(3).Find(&sales); err != nil || len(sales) != 2 || sales[0].ID != 4 {
+		t.Fatal("failed to query with offset only", sales, err)
+	}
+	sales = nil
+	if err := s.Select("Region").GroupBy("Region").Having("sum(Amount) > ?", 25).Orderby("Region").Find(&sales); err != nil ||
+		len(sales) != 2 || sales[0].Region != "east" || sales[1].Region != "north" {
+		t.Fatal("failed to group by region", sales, err)
+	}
+	if err := s.Select("Unknown").Find(&sales); err == nil {
+		t.Fatal("expect error for unknown column")
+	}
+}
@@ -0,0 +1,193 @@
+package session
+
+import (
+	"fmt"
+	"geeorm/clause"
+	"geeorm/schema"
+	"reflect"
+)
+
+// Association 多对多关联的操作入口，用于维护连接表中的关联关系，不会插入或修改关联对象本身
+type Association struct {
+	s        *Session
+	owner    reflect.Value
+	rel      *schema.Relationship
+	relTable *schema.Schema
+	Error    error
+}
+
+// Association 获取模型对象上某个多对多关联字段的操作入口，需要先通过 Model 传入对象指针
+// 例如 s.Model(&user).Association("Roles").Append(&role)
+func (s *Session) Association(name string) *Association {
+	a := &Association{s: s}
+	if a.Error = s.modelErr(); a.Error != nil {
+		return a
+	}
+	table := s.GetrefTable()
+	rel := table.GetRelationship(name)
+	switch {
+	case rel == nil:
+		a.Error = fmt.Errorf("relationship %s is not exists in %s", name, table.Name)
+	case rel.Kind != schema.ManyToMany:
+		a.Error = fmt.Errorf("relationship %s of %s is not many2many", name, table.Name)
+	case reflect.ValueOf(table.Model).Kind() != reflect.Ptr:
+		a.Error = fmt.Errorf("model of %s must be a pointer to use association", table.Name)
+	default:
+		a.owner = reflect.ValueOf(table.Model).Elem()
+		a.rel = rel
+		a.relTable, a.Error = s.parseType(rel.Type)
+	}
+	return a
+}
+
+// Append 为对象追加关联关系，已存在的关联关系不会重复写入
+func (a *Association) Append(values ...interface{}) error {
+	if a.Error != nil {
+		return a.Error
+	}
+	refs, err := a.refKeys(values)
+	if err != nil || len(refs) == 0 {
+		return err
+	}
+	_, err = a.s.Transaction(func(s *Session) (result interface{}, err error) {
+		if err = a.unlink(s, refs); err != nil {
+			return
+		}
+		var records []interface{}
+		for _, ref := range refs {
+			records = append(records, []interface{}{a.ownerKey(), ref})
+		}
+		s.clause.Set(clause.INSERT, a.rel.JoinTable, []string{a.rel.JoinForeignKey, a.rel.JoinReferences})
+		s.clause.Set(clause.VALUES, records...)
+		sql, vars := s.clause.Build(clause.INSERT, clause.VALUES)
+		return s.Raw(sql, vars...).Exec()
+	})
+	if err != nil {
+		return err
+	}
+	a.removeFromField(refs)
+	field := a.owner.FieldByName(a.rel.Name)
+	for _, value := range values {
+		field.Set(reflect.Append(field, elemValue(field.Type().Elem(), value)))
+	}
+	return nil
+}
+
+// Replace 使用给定的对象替换对象现有的全部关联关系
+func (a *Association) Replace(values ...interface{}) error {
+	if a.Error != nil {
+		return a.Error
+	}
+	_, err := a.s.Transaction(func(s *Session) (interface{}, error) {
+		if err := a.Clear(); err != nil {
+			return nil, err
+		}
+		return nil, a.Append(values...)
+	})
+	return err
+}
+
+// Delete 删除对象与给定对象之间的关联关系
+func (a *Association) Delete(values ...interface{}) error {
+	if a.Error != nil {
+		return a.Error
+	}
+	refs, err := a.refKeys(values)
+	if err != nil || len(refs) == 0 {
+		return err
+	}
+	if err = a.unlink(a.s, refs); err != nil {
+		return err
+	}
+	a.removeFromField(refs)
+	return nil
+}
+
+// Clear 删除对象的全部关联关系
+func (a *Association) Clear() error {
+	if a.Error != nil {
+		return a.Error
+	}
+	if err := a.unlink(a.s, nil); err != nil {
+		return err
+	}
+	field := a.owner.FieldByName(a.rel.Name)
+	field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+	return nil
+}
+
+// Count 统计对象的关联关系数量
+func (a *Association) Count() (int64, error) {
+	if a.Error != nil {
+		return 0, a.Error
+	}
+	s := a.s
+	s.clause.Set(clause.COUNT, a.rel.JoinTable)
+	s.Where(clause.Eq(a.rel.JoinForeignKey, a.ownerKey()))
+	sql, vars := s.clause.Build(clause.COUNT, clause.WHERE)
+	var count int64
+	if err := s.Raw(sql, vars...).QueryRow().Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ownerKey 返回本对象被连接表引用的字段值
+func (a *Association) ownerKey() interface{} {
+	return a.owner.FieldByName(a.rel.ForeignKey).Interface()
+}
+
+// refKeys 返回一组关联对象被连接表引用的字段值
+func (a *Association) refKeys(values []interface{}) ([]interface{}, error) {
+	var refs []interface{}
+	for _, value := range values {
+		v := reflect.Indirect(reflect.ValueOf(value))
+		if v.Type() != a.rel.Type {
+			return nil, fmt.Errorf("expect %s, but got %s", a.rel.Type.Name(), v.Type().Name())
+		}
+		refs = append(refs, v.FieldByName(a.rel.References).Interface())
+	}
+	return refs, nil
+}
+
+// unlink 删除连接表中本对象与给定关联对象之间的关联关系，refs 为空时删除本对象的全部关联关系
+func (a *Association) unlink(s *Session, refs []interface{}) error {
+	s.clause.Set(clause.DELETE, a.rel.JoinTable)
+	s.Where(clause.Eq(a.rel.JoinForeignKey, a.ownerKey()))
+	if len(refs) > 0 {
+		s.Where(clause.In(a.rel.JoinReferences, refs...))
+	}
+	sql, vars := s.clause.Build(clause.DELETE, clause.WHERE)
+	_, err := s.Raw(sql, vars...).Exec()
+	return err
+}
+
+// removeFromField 从对象的关联字段中移除给定的关联对象
+func (a *Association) removeFromField(refs []interface{}) {
+	removed := make(map[string]bool)
+	for _, ref := range refs {
+		removed[fmt.Sprint(ref)] = true
+	}
+	field := a.owner.FieldByName(a.rel.Name)
+	kept := reflect.MakeSlice(field.Type(), 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		if key, ok := fieldKey(field.Index(i), a.rel.References); !ok || !removed[key] {
+			kept = reflect.Append(kept, field.Index(i))
+		}
+	}
+	field.Set(kept)
+}
+
+// elemValue 将对象转换为切片元素类型，元素可以是结构体或结构体指针
+func elemValue(elemType reflect.Type, value interface{}) reflect.Value {
+	v := reflect.ValueOf(value)
+	switch {
+	case elemType.Kind() == reflect.Ptr && v.Kind() != reflect.Ptr:
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		return ptr
+	case elemType.Kind() != reflect.Ptr && v.Kind() == reflect.Ptr:
+		return v.Elem()
+	}
+	return v
+}
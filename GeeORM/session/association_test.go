@@ -0,0 +1,68 @@
+package session
+
+import "testing"
+
+type Student struct {
+	ID      int `geeorm:"primaryKey"`
+	Name    string
+	Courses []*Course `geeorm:"many2many:student_courses"`
+}
+
+type Course struct {
+	ID    int `geeorm:"primaryKey"`
+	Title string
+}
+
+func testAssociationInit(t *testing.T) (*Session, []*Course) {
+	t.Helper()
+	s := NewSession()
+	_, _ = s.Raw("DROP TABLE IF EXISTS student_courses").Exec()
+	courses := []*Course{{1, "Math"}, {2, "Art"}, {3, "Music"}}
+	for _, model := range []interface{}{&Student{}, &Course{}} {
+		if s.Model(model).DropTable() != nil || s.Model(model).CreateTable() != nil {
+			t.Fatal("failed to create table")
+		}
+	}
+	_, err1 := s.Insert(&Student{ID: 1, Name: "Tom"}, &Student{ID: 2, Name: "Sam"})
+	_, err2 := s.Insert(courses[0], courses[1], courses[2])
+	if err1 != nil || err2 != nil {
+		t.Fatal("failed init test records")
+	}
+	return s, courses
+}
+
+func TestSession_Association(t *testing.T) {
+	s, courses := testAssociationInit(t)
+	tom := &Student{ID: 1, Name: "Tom"}
+	if err := s.Model(tom).Association("Courses").Append(courses[0], courses[1]); err != nil {
+		t.Fatal("failed to append association", err)
+	}
+	_ = s.Model(tom).Association("Courses").Append(courses[1])
+	if count, err := s.Model(tom).Association("Courses").Count(); err != nil || count != 2 || len(tom.Courses) != 2 {
+		t.Fatal("failed to count association, got", count, err)
+	}
+	if err := s.Model(tom).Association("Courses").Delete(courses[0]); err != nil || len(tom.Courses) != 1 {
+		t.Fatal("failed to delete association", err)
+	}
+	if err := s.Model(tom).Association("Courses").Replace(courses[0], courses[2]); err != nil || len(tom.Courses) != 2 {
+		t.Fatal("failed to replace association", err)
+	}
+
+	var students []Student
+	if err := s.Preload("Courses").Orderby("ID").Find(&students); err != nil || len(students) != 2 {
+		t.Fatal("failed to query students", err)
+	}
+	if len(students[0].Courses) != 2 || students[0].Courses[1].Title != "Music" || len(students[1].Courses) != 0 {
+		t.Fatal("failed to preload many2many relationship, got", students)
+	}
+
+	if err := s.Model(tom).Association("Courses").Clear(); err != nil || len(tom.Courses) != 0 {
+		t.Fatal("failed to clear association", err)
+	}
+	if count, _ := s.Model(tom).Association("Courses").Count(); count != 0 {
+		t.Fatal("failed to clear association, got", count)
+	}
+	if _, err := s.Model(tom).Association("Name").Count(); err == nil {
+		t.Fatal("expect error of not many2many relationship")
+	}
+}
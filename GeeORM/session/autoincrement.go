@@ -0,0 +1,138 @@
+package session
+
+import (
+	"geeorm/clause"
+	"geeorm/schema"
+	"reflect"
+)
+
+// generatedField 返回需要由数据库生成值的自增列，模型没有自增列或有对象显式设置了自增值时返回 nil
+func generatedField(table *schema.Schema, values []interface{}) *schema.Field {
+	field := table.AutoIncrementField
+	if field == nil {
+		return nil
+	}
+	for _, value := range values {
+		if !reflect.Indirect(reflect.ValueOf(value)).FieldByName(field.GoName).IsZero() {
+			return nil
+		}
+	}
+	return field
+}
+
+// insertColumns 返回INSERT语句中的列名，由数据库生成的自增列不写入
+func insertColumns(table *schema.Schema, auto *schema.Field) []string {
+	if auto == nil {
+		return table.FieldNames
+	}
+	var names []string
+	for _, field := range table.Fields {
+		if field != auto {
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
+
+// insertValues 返回对象中与 insertColumns 对应的值
+func insertValues(table *schema.Schema, auto *schema.Field, value interface{}) []interface{} {
+	if auto == nil {
+		return table.RecordValues(value)
+	}
+	dest := reflect.Indirect(reflect.ValueOf(value))
+	var vars []interface{}
+	for _, field := range table.Fields {
+		if field != auto {
+			vars = append(vars, field.ValueOf(dest.FieldByName(field.GoName)))
+		}
+	}
+	return vars
+}
+
+// insertExec 执行INSERT语句，auto 不为 nil 时根据 LastInsertId 推算每一行的自增值并写回对象
+// 插入多行时假定自增值连续，方言决定 LastInsertId 对应第一行还是最后一行，无法保证连续的 mysql 由 insertEach 逐行插入
+func (s *Session) insertExec(sql string, vars []interface{}, auto *schema.Field, values []interface{}) (int64, error) {
+	result, err := s.Raw(sql, vars...).Exec()
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || auto == nil {
+		return affected, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	first := id
+	if !s.dial.LastInsertIDIsFirst() {
+		first = id - int64(len(values)) + 1
+	}
+	for i, value := range values {
+		setGenerated(value, auto, first+int64(i))
+	}
+	return affected, nil
+}
+
+// insertEach 在事务中逐行执行INSERT语句并写回每一行的自增值
+// mysql 在 innodb_autoinc_lock_mode=2 或 auto_increment_increment 不为 1 时，一条语句插入的多行自增值不一定连续，
+// 无法由 LastInsertId 推算，因此需要写回自增值时逐行插入
+func (s *Session) insertEach(orders []clause.Type, recordValues []interface{}, auto *schema.Field, values []interface{}) (int64, error) {
+	// 执行语句会清空链式调用的状态，先构造每一行的语句
+	sqls, vars := make([]string, len(values)), make([][]interface{}, len(values))
+	for i := range values {
+		s.clause.Set(clause.VALUES, recordValues[i])
+		sqls[i], vars[i] = s.clause.Build(orders...)
+	}
+	var affected int64
+	_, err := s.Transaction(func(s *Session) (result interface{}, err error) {
+		for i := range values {
+			n, err := s.insertExec(sqls[i], vars[i], auto, values[i:i+1])
+			if err != nil {
+				return nil, err
+			}
+			affected += n
+		}
+		return
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// insertReturning 执行带 RETURNING 的INSERT语句，按返回的顺序将自增值写回对象
+func (s *Session) insertReturning(sql string, vars []interface{}, auto *schema.Field, values []interface{}) (int64, error) {
+	rows, err := s.Raw(sql, vars...).QueryRows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var affected int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return 0, err
+		}
+		if int(affected) < len(values) {
+			setGenerated(values[affected], auto, id)
+		}
+		affected++
+	}
+	return affected, rows.Err()
+}
+
+// setGenerated 将数据库生成的自增值写入对象的自增字段，对象不是指针时忽略
+func setGenerated(value interface{}, auto *schema.Field, id int64) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr {
+		return
+	}
+	f := v.Elem().FieldByName(auto.GoName)
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f.SetUint(uint64(id))
+	}
+}
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"geeorm/dialect"
+	"testing"
+)
+
+type Ticket struct {
+	ID    int64 `geeorm:"primaryKey;autoIncrement"`
+	Title string
+}
+
+func TestSession_InsertAutoIncrement(t *testing.T) {
+	s := NewSession().Model(&Ticket{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+
+	first := &Ticket{Title: "first"}
+	if _, err := s.Insert(first); err != nil || first.ID != 1 {
+		t.Fatal("failed to populate id, got", first.ID, err)
+	}
+	second, third := &Ticket{Title: "second"}, &Ticket{Title: "third"}
+	if n, err := s.Insert(second, third); err != nil || n != 2 || second.ID != 2 || third.ID != 3 {
+		t.Fatal("failed to populate ids of multi-row insert, got", second.ID, third.ID, err)
+	}
+	if n, err := s.Insert(); err != nil || n != 0 {
+		t.Fatal("expect empty insert to do nothing", n, err)
+	}
+	explicit := &Ticket{ID: 10, Title: "explicit"}
+	if _, err := s.Insert(explicit); err != nil || explicit.ID != 10 {
+		t.Fatal("failed to insert explicit id", explicit.ID, err)
+	}
+
+	batch := make([]Ticket, 5)
+	if _, err := s.InsertBatch(batch, 2); err != nil {
+		t.Fatal(err)
+	}
+	for i, ticket := range batch {
+		if ticket.ID != int64(11+i) {
+			t.Fatalf("expect id %d, but got %d", 11+i, ticket.ID)
+		}
+	}
+}
+
+// firstIDDialect 与 mysql 一样 LastInsertId 为第一行自增值的方言
+type firstIDDialect struct {
+	dialect.Dialect
+}
+
+func (firstIDDialect) LastInsertIDIsFirst() bool {
+	return true
+}
+
+func TestSession_InsertEach(t *testing.T) {
+	s := NewSession().Model(&Ticket{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+
+	s = New(TestDB, firstIDDialect{s.dial})
+	_, _ = s.Raw("INSERT INTO Ticket (ID, Title) VALUES (5, 'gap')").Exec()
+	tickets := []*Ticket{{Title: "a"}, {Title: "b"}, {Title: "c"}}
+	if n, err := s.Insert(tickets[0], tickets[1], tickets[2]); err != nil || n != 3 {
+		t.Fatal("failed to insert tickets", n, err)
+	}
+	for i, ticket := range tickets {
+		var title string
+		if err := s.Raw("SELECT Title FROM Ticket WHERE ID = ?", ticket.ID).QueryRow().Scan(&title); err != nil || title != ticket.Title {
+			t.Fatalf("expect id %d to hold %q, but got %q", ticket.ID, tickets[i].Title, title)
+		}
+	}
+}
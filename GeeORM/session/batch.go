@@ -0,0 +1,82 @@
+package session
+
+import (
+	"errors"
+	"reflect"
+)
+
+// BatchProgress 批量插入的进度回调，batch 为已完成的批次序号(从1开始)，inserted 为已插入的行数，total 为总行数
+type BatchProgress func(batch int, inserted int64, total int)
+
+// WithProgress 为本次链式调用中的 InsertBatch 设置进度回调，每完成一批调用一次
+func (s *Session) WithProgress(f BatchProgress) *Session {
+	s.progress = f
+	return s
+}
+
+// InsertBatch 将结构体或结构体指针的切片分批插入数据库，返回插入的总行数
+// 每批的行数不超过 batchSize，且绑定的参数个数不超过方言的上限，batchSize 不大于0时仅受参数个数限制
+// 全部批次在一个事务中执行，任意一批失败时全部回滚；Table、OnConflict 等链式设置对每一批都生效
+func (s *Session) InsertBatch(values interface{}, batchSize int) (int64, error) {
+	slice := reflect.Indirect(reflect.ValueOf(values))
+	if slice.Kind() != reflect.Slice {
+		s.Clear()
+		return 0, errors.New("InsertBatch expects a slice of structs")
+	}
+	total := slice.Len()
+	if total == 0 {
+		s.Clear()
+		return 0, nil
+	}
+	// 每一批的 Insert 都会清空链式调用的状态，先记录需要对每一批生效的设置
+	table, conflict, progress := s.table, s.conflict, s.progress
+	if err := s.Model(elemPointer(slice, 0)).modelErr(); err != nil {
+		return 0, err
+	}
+	model := s.GetrefTable()
+	fields := len(model.Fields)
+	if fields == 0 {
+		s.Clear()
+		return 0, errors.New("no fields of " + model.Name + " to insert")
+	}
+	if limit := s.dial.MaxBindVars() / fields; batchSize <= 0 || batchSize > limit {
+		batchSize = limit
+	}
+
+	var inserted int64
+	_, err := s.Transaction(func(s *Session) (result interface{}, err error) {
+		for start, batch := 0, 1; start < total; start, batch = start+batchSize, batch+1 {
+			end := start + batchSize
+			if end > total {
+				end = total
+			}
+			rows := make([]interface{}, 0, end-start)
+			for i := start; i < end; i++ {
+				rows = append(rows, elemPointer(slice, i))
+			}
+			s.table, s.conflict = table, conflict
+			affected, err := s.Insert(rows...)
+			if err != nil {
+				return nil, err
+			}
+			inserted += affected
+			if progress != nil {
+				progress(batch, inserted, total)
+			}
+		}
+		return
+	})
+	if err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+// elemPointer 返回切片中第i个元素的指针，以便钩子与自动时间戳修改元素本身，元素本身为指针时原样返回
+func elemPointer(slice reflect.Value, i int) interface{} {
+	elem := slice.Index(i)
+	if elem.Kind() == reflect.Ptr || !elem.CanAddr() {
+		return elem.Interface()
+	}
+	return elem.Addr().Interface()
+}
@@ -0,0 +1,49 @@
+package session
+
+import "testing"
+
+type Reading struct {
+	ID    int `geeorm:"primaryKey"`
+	Value float64
+}
+
+func TestSession_InsertBatch(t *testing.T) {
+	s := NewSession().Model(&Reading{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	readings := make([]Reading, 2500)
+	for i := range readings {
+		readings[i] = Reading{ID: i + 1, Value: float64(i)}
+	}
+	var batches int
+	var last int64
+	affected, err := s.WithProgress(func(batch int, inserted int64, total int) {
+		batches, last = batch, inserted
+		if total != len(readings) {
+			t.Fatal("expect total 2500, but got", total)
+		}
+	}).InsertBatch(readings, 1000)
+	if err != nil || affected != 2500 {
+		t.Fatal("failed to insert batch", affected, err)
+	}
+	// sqlite3 每条语句最多绑定 999 个参数，每行2个参数，因此每批最多 499 行
+	if batches != 6 || last != 2500 {
+		t.Fatal("expect 6 batches, but got", batches, last)
+	}
+	if count, _ := s.Count(); count != 2500 {
+		t.Fatal("expect 2500 rows, but got", count)
+	}
+
+	// 任意一批失败时全部回滚
+	more := []*Reading{{ID: 2501}, {ID: 2502}, {ID: 1}}
+	if _, err := s.InsertBatch(more, 2); err == nil {
+		t.Fatal("expect primary key conflict")
+	}
+	if count, _ := s.Count(); count != 2500 {
+		t.Fatal("expect batch insert to roll back, but got", count)
+	}
+	type Empty struct{}
+	if _, err := s.InsertBatch([]Empty{{}}, 10); err == nil {
+		t.Fatal("expect error for model without fields")
+	}
+}
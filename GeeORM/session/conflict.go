@@ -0,0 +1,54 @@
+package session
+
+import (
+	"geeorm/clause"
+	"geeorm/schema"
+)
+
+// Conflict 插入冲突处理的构造器，由 Session.OnConflict 创建，通过 DoUpdate 或 DoNothing 回到会话继续链式调用
+type Conflict struct {
+	s       *Session
+	columns []string
+}
+
+// OnConflict 为本次链式调用中的 Insert 设置冲突处理，columns 为判断冲突的列，为空时使用主键
+// 例如 s.OnConflict("Email").DoUpdate("Name").Insert(&user)
+func (s *Session) OnConflict(columns ...string) *Conflict {
+	return &Conflict{s: s, columns: columns}
+}
+
+// DoUpdate 冲突时使用插入的值更新给定的列，未指定列时更新除冲突列、主键及创建时间外的全部列
+func (c *Conflict) DoUpdate(columns ...string) *Session {
+	c.s.conflict = &clause.OnConflict{Columns: c.columns, DoUpdates: columns}
+	return c.s
+}
+
+// DoNothing 冲突时忽略插入的行
+func (c *Conflict) DoNothing() *Session {
+	c.s.conflict = &clause.OnConflict{Columns: c.columns, DoNothing: true}
+	return c.s
+}
+
+// onConflict 根据表信息补全冲突处理中未指定的列
+func (s *Session) onConflict(table *schema.Schema) clause.OnConflict {
+	conflict := *s.conflict
+	if len(conflict.Columns) == 0 {
+		for _, field := range table.PrimaryFields {
+			conflict.Columns = append(conflict.Columns, field.Name)
+		}
+	}
+	if conflict.DoNothing || len(conflict.DoUpdates) > 0 {
+		return conflict
+	}
+	skip := make(map[string]bool)
+	for _, col := range conflict.Columns {
+		skip[col] = true
+	}
+	for _, field := range table.Fields {
+		if skip[field.Name] || field.PrimaryKey || field.AutoCreateTime && !field.AutoUpdateTime {
+			continue
+		}
+		conflict.DoUpdates = append(conflict.DoUpdates, field.Name)
+	}
+	return conflict
+}
@@ -0,0 +1,35 @@
+package session
+
+import "testing"
+
+type Subscriber struct {
+	ID    int    `geeorm:"primaryKey"`
+	Email string `geeorm:"unique"`
+	Name  string
+}
+
+func TestSession_OnConflict(t *testing.T) {
+	s := NewSession().Model(&Subscriber{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	if _, err := s.Insert(&Subscriber{ID: 1, Email: "tom@example.com", Name: "Tom"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.OnConflict().DoUpdate().Insert(&Subscriber{ID: 1, Email: "tom@example.com", Name: "Tommy"}); err != nil {
+		t.Fatal("failed to upsert by primary key", err)
+	}
+	if _, err := s.OnConflict("Email").DoUpdate("Name").Insert(&Subscriber{ID: 2, Email: "tom@example.com", Name: "Thomas"}); err != nil {
+		t.Fatal("failed to upsert by unique column", err)
+	}
+	if _, err := s.OnConflict("Email").DoNothing().Insert(&Subscriber{ID: 3, Email: "tom@example.com", Name: "Ignored"}); err != nil {
+		t.Fatal("failed to ignore conflict", err)
+	}
+	var subscribers []Subscriber
+	if err := s.Find(&subscribers); err != nil || len(subscribers) != 1 || subscribers[0].ID != 1 || subscribers[0].Name != "Thomas" {
+		t.Fatal("failed to upsert, got", subscribers, err)
+	}
+	if _, err := s.Insert(&Subscriber{ID: 1, Email: "sam@example.com", Name: "Sam"}); err == nil {
+		t.Fatal("conflict handling should not leak into later calls")
+	}
+}
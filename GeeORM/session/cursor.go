@@ -0,0 +1,108 @@
+package session
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrStopIteration 在 Iterate 的回调函数中返回该错误可以提前结束遍历，Iterate 将返回 nil
+var ErrStopIteration = errors.New("geeorm: stop iteration")
+
+// Cursor 逐行读取查询结果的游标，由 Session.Rows 创建，使用完毕后必须调用 Close
+type Cursor struct {
+	s       *Session
+	rows    *sql.Rows
+	typ     reflect.Type
+	scanner *rowScanner
+}
+
+// Rows 按照当前链式调用的条件查询 model 对应的表，返回逐行读取结果的游标
+// 与 Find 不同，结果不会一次全部读入内存，适用于大量数据的导出
+func (s *Session) Rows(model interface{}) (*Cursor, error) {
+	if err := s.Model(model).modelErr(); err != nil {
+		return nil, err
+	}
+	table := s.GetrefTable()
+	rows, err := s.queryModel()
+	if err != nil {
+		return nil, err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	return &Cursor{
+		s:       s,
+		rows:    rows,
+		typ:     reflect.Indirect(reflect.ValueOf(model)).Type(),
+		scanner: &rowScanner{columns: columns, fields: fieldsByColumn(table.Fields, columns)},
+	}, nil
+}
+
+// Next 移动到下一行，没有更多的行或发生错误时返回 false
+func (c *Cursor) Next() bool {
+	return c.rows.Next()
+}
+
+// Scan 将当前行写入 dest 并执行 AfterQuery 钩子，dest 为模型的指针
+func (c *Cursor) Scan(dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Type() != c.typ {
+		return fmt.Errorf("expect *%s, but got %T", c.typ, dest)
+	}
+	if err := c.scanner.scan(c.rows, v.Elem()); err != nil {
+		return err
+	}
+	return c.s.CallMethod(AfterQuery, dest)
+}
+
+// Err 返回遍历过程中发生的错误
+func (c *Cursor) Err() error {
+	return c.rows.Err()
+}
+
+// Close 关闭游标并释放数据库连接，可以重复调用
+func (c *Cursor) Close() error {
+	return c.rows.Close()
+}
+
+// errorType error 接口的类型
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Iterate 逐行遍历查询结果，fn 的形式为 func(*Model) error，模型由参数类型决定
+// fn 返回 ErrStopIteration 时提前结束遍历并返回 nil，返回其他错误时结束遍历并返回该错误
+// 无论以何种方式结束，底层的结果集都会被关闭
+func (s *Session) Iterate(fn interface{}) (err error) {
+	f := reflect.ValueOf(fn)
+	typ := f.Type()
+	if typ.Kind() != reflect.Func || typ.NumIn() != 1 || typ.In(0).Kind() != reflect.Ptr ||
+		typ.NumOut() != 1 || typ.Out(0) != errorType {
+		s.Clear()
+		return fmt.Errorf("expect func(*Model) error, but got %s", typ)
+	}
+	cursor, err := s.Rows(reflect.New(typ.In(0).Elem()).Interface())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := cursor.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	for cursor.Next() {
+		dest := reflect.New(typ.In(0).Elem())
+		if err = cursor.Scan(dest.Interface()); err != nil {
+			return
+		}
+		if out := f.Call([]reflect.Value{dest})[0]; !out.IsNil() {
+			if err = out.Interface().(error); err == ErrStopIteration {
+				err = nil
+			}
+			return
+		}
+	}
+	return cursor.Err()
+}
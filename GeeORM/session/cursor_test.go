@@ -0,0 +1,94 @@
+package session
+
+import (
+	"errors"
+	"testing"
+)
+
+type Metric struct {
+	ID      int `geeorm:"primaryKey"`
+	Value   int
+	Doubled int `geeorm:"-"`
+}
+
+func (m *Metric) AfterQuery(s *Session) error {
+	m.Doubled = m.Value * 2
+	return nil
+}
+
+func prepareMetrics(t *testing.T) *Session {
+	t.Helper()
+	s := NewSession().Model(&Metric{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	metrics := make([]Metric, 10)
+	for i := range metrics {
+		metrics[i] = Metric{ID: i + 1, Value: i + 1}
+	}
+	if _, err := s.InsertBatch(metrics, 0); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSession_Iterate(t *testing.T) {
+	s := prepareMetrics(t)
+	sum := 0
+	err := s.Where("Value > ?", 5).Iterate(func(m *Metric) error {
+		sum += m.Doubled
+		return nil
+	})
+	if err != nil || sum != 2*(6+7+8+9+10) {
+		t.Fatal("failed to iterate, got", sum, err)
+	}
+
+	visited := 0
+	err = s.Orderby("ID").Iterate(func(m *Metric) error {
+		visited++
+		if m.ID == 3 {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil || visited != 3 {
+		t.Fatal("failed to stop iteration early", visited, err)
+	}
+
+	boom := errors.New("boom")
+	if err := s.Iterate(func(m *Metric) error { return boom }); err != boom {
+		t.Fatal("expect callback error, but got", err)
+	}
+	if err := s.Iterate(func(m Metric) {}); err == nil {
+		t.Fatal("expect error for invalid callback")
+	}
+	// 提前结束后结果集已关闭，会话可以继续使用
+	if n, err := s.Count(); err != nil || n != 10 {
+		t.Fatal("expect 10, but got", n, err)
+	}
+}
+
+func TestSession_Rows(t *testing.T) {
+	s := prepareMetrics(t)
+	cursor, err := s.Orderby("ID DESC").Limit(3).Rows(&Metric{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cursor.Close()
+	var ids []int
+	for cursor.Next() {
+		var m Metric
+		if err := cursor.Scan(&m); err != nil {
+			t.Fatal(err)
+		}
+		if m.Doubled != m.Value*2 {
+			t.Fatal("expect AfterQuery to run for each row")
+		}
+		ids = append(ids, m.ID)
+	}
+	if err := cursor.Err(); err != nil || len(ids) != 3 || ids[0] != 10 {
+		t.Fatal("failed to read rows", ids, err)
+	}
+	if err := cursor.Scan(&Sale{}); err == nil {
+		t.Fatal("expect error for mismatched destination")
+	}
+}
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"fmt"
 	"geeorm/log"
 	"reflect"
 )
@@ -17,19 +18,102 @@ const (
 	AfterInsert  = "AfterInsert"
 )
 
-// CallMethod 调用钩子函数的入口
-func (s *Session) CallMethod(method string, value interface{}) {
-	function := reflect.ValueOf(s.GetrefTable().Model).MethodByName(method)
-	if value != nil {
-		function = reflect.ValueOf(value).MethodByName(method)
+// BeforeQueryer 查询前调用的钩子，返回错误时终止查询
+type BeforeQueryer interface {
+	BeforeQuery(s *Session) error
+}
+
+// AfterQueryer 每一行查询结果扫描完成后调用的钩子
+type AfterQueryer interface {
+	AfterQuery(s *Session) error
+}
+
+// BeforeUpdater 更新前调用的钩子，返回错误时终止更新
+type BeforeUpdater interface {
+	BeforeUpdate(s *Session) error
+}
+
+// AfterUpdater 更新后调用的钩子
+type AfterUpdater interface {
+	AfterUpdate(s *Session) error
+}
+
+// BeforeDeleter 删除前调用的钩子，返回错误时终止删除
+type BeforeDeleter interface {
+	BeforeDelete(s *Session) error
+}
+
+// AfterDeleter 删除后调用的钩子
+type AfterDeleter interface {
+	AfterDelete(s *Session) error
+}
+
+// BeforeInserter 插入前调用的钩子，返回错误时终止插入
+type BeforeInserter interface {
+	BeforeInsert(s *Session) error
+}
+
+// AfterInserter 插入后调用的钩子
+type AfterInserter interface {
+	AfterInsert(s *Session) error
+}
+
+// CallMethod 调用钩子函数的入口，value 为 nil 时在会话维护的模型对象上调用
+// 钩子通过接口断言查找，返回的错误会交由调用方终止当前操作，在事务中时由事务回滚
+// 需要修改对象的钩子必须传入对象指针，钩子修改了按值传入的对象时返回错误
+func (s *Session) CallMethod(method string, value interface{}) (err error) {
+	if value == nil {
+		value = s.GetrefTable().Model
 	}
-	param := []reflect.Value{reflect.ValueOf(s)}
-	if function.IsValid() {
-		if v := function.Call(param); len(v) > 0 {
-			if err, ok := v[0].Interface().(error); ok {
+	// 指针接收者实现的钩子只存在于指针的方法集中，因此为非指针对象构造一个副本的指针
+	if v := reflect.ValueOf(value); v.Kind() != reflect.Ptr {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		value = ptr.Interface()
+		defer func() {
+			// 对副本的修改不会写回调用方的对象，静默丢弃会导致写入未经钩子处理的数据
+			if err == nil && !reflect.DeepEqual(v.Interface(), ptr.Elem().Interface()) {
+				err = fmt.Errorf("hook %s modified a copy of %s, pass a pointer instead", method, v.Type())
 				log.Error(err)
 			}
+		}()
+	}
+	switch method {
+	case BeforeQuery:
+		if hook, ok := value.(BeforeQueryer); ok {
+			err = hook.BeforeQuery(s)
 		}
+	case AfterQuery:
+		if hook, ok := value.(AfterQueryer); ok {
+			err = hook.AfterQuery(s)
+		}
+	case BeforeUpdate:
+		if hook, ok := value.(BeforeUpdater); ok {
+			err = hook.BeforeUpdate(s)
+		}
+	case AfterUpdate:
+		if hook, ok := value.(AfterUpdater); ok {
+			err = hook.AfterUpdate(s)
+		}
+	case BeforeDelete:
+		if hook, ok := value.(BeforeDeleter); ok {
+			err = hook.BeforeDelete(s)
+		}
+	case AfterDelete:
+		if hook, ok := value.(AfterDeleter); ok {
+			err = hook.AfterDelete(s)
+		}
+	case BeforeInsert:
+		if hook, ok := value.(BeforeInserter); ok {
+			err = hook.BeforeInsert(s)
+		}
+	case AfterInsert:
+		if hook, ok := value.(AfterInserter); ok {
+			err = hook.AfterInsert(s)
+		}
+	}
+	if err != nil {
+		log.Error(err)
 	}
-	return 
-}
\ No newline at end of file
+	return
+}
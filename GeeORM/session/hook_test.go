@@ -1,6 +1,7 @@
 package session
 
 import (
+	"errors"
 	"geeorm/log"
 	"testing"
 )
@@ -34,4 +35,75 @@ func TestSession_CallMethod(t *testing.T) {
 	if err != nil || u.ID != 1001 || u.Password != "******" {
 		t.Fatal("Failed to call hooks after query, got", u)
 	}
-}
\ No newline at end of file
+}
+
+type Ledger struct {
+	ID      int `geeorm:"PRIMARY KEY"`
+	Balance int
+	updated int
+}
+
+func (l *Ledger) BeforeInsert(s *Session) error {
+	if l.Balance < 0 {
+		return errors.New("negative balance")
+	}
+	return nil
+}
+
+func (l *Ledger) AfterUpdate(s *Session) error {
+	l.updated++
+	return nil
+}
+
+func (l *Ledger) BeforeDelete(s *Session) error {
+	return errors.New("ledger can not be deleted")
+}
+
+func TestSession_HookAbort(t *testing.T) {
+	s := NewSession().Model(&Ledger{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	if _, err := s.Insert(&Ledger{ID: 1, Balance: -1}); err == nil {
+		t.Fatal("expect BeforeInsert to abort the insert")
+	}
+
+	ledger := &Ledger{ID: 1, Balance: 10}
+	_, err := s.Transaction(func(s *Session) (result interface{}, err error) {
+		if _, err = s.Insert(ledger); err != nil {
+			return
+		}
+		if _, err = s.Model(ledger).Where("ID = ?", 1).Update("Balance", 20); err != nil {
+			return
+		}
+		return s.Model(ledger).Where("ID = ?", 1).Delete()
+	})
+	if err == nil || ledger.updated != 1 {
+		t.Fatal("expect BeforeDelete to abort the transaction, got", err)
+	}
+	if count, _ := s.Model(&Ledger{}).Count(); count != 0 {
+		t.Fatal("failed to rollback the transaction, got", count)
+	}
+}
+
+func TestSession_HookByValue(t *testing.T) {
+	s := NewSession().Model(&Account{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	if _, err := s.Insert(Account{1, "123456"}); err == nil {
+		t.Fatal("expect error when BeforeInsert modifies a copy")
+	}
+	if count, _ := s.Model(&Account{}).Count(); count != 0 {
+		t.Fatal("expect nothing inserted, got", count)
+	}
+
+	// 只做校验而不修改对象的钩子可以接收按值传入的对象
+	s = NewSession().Model(&Ledger{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	if _, err := s.Insert(Ledger{ID: 1, Balance: 10}); err != nil {
+		t.Fatal("failed to insert by value", err)
+	}
+	if _, err := s.Insert(Ledger{ID: 2, Balance: -1}); err == nil || err.Error() != "negative balance" {
+		t.Fatal("expect BeforeInsert to abort the insert, got", err)
+	}
+}
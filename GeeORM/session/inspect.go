@@ -0,0 +1,123 @@
+package session
+
+import (
+	"database/sql"
+	"geeorm/dialect"
+)
+
+// TableExists 检查数据库中是否存在名为 name 的表
+func (s *Session) TableExists(name string) bool {
+	query, values := s.dial.TableExistSQL(name)
+	row := s.Raw(query, values...).QueryRow()
+	var tmp string
+	_ = row.Scan(&tmp)
+	return tmp == name
+}
+
+// Columns 查询数据库中表 table 的实际列定义
+func (s *Session) Columns(table string) ([]dialect.Column, error) {
+	query, values := s.dial.ColumnsSQL(table)
+	if query == "" {
+		return nil, dialect.ErrNotSupported
+	}
+	rows, err := s.Raw(query, values...).QueryRows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var columns []dialect.Column
+	for rows.Next() {
+		var col dialect.Column
+		var def sql.NullString
+		if err := rows.Scan(&col.Name, &col.Type, &col.NotNull, &def, &col.PrimaryKey); err != nil {
+			return nil, err
+		}
+		if def.Valid {
+			col.Default = &def.String
+		}
+		columns = append(columns, col)
+	}
+	return columns, rows.Err()
+}
+
+// Indexes 查询数据库中表 table 上的索引
+func (s *Session) Indexes(table string) ([]dialect.Index, error) {
+	query, values := s.dial.IndexesSQL(table)
+	if query == "" {
+		return nil, dialect.ErrNotSupported
+	}
+	rows, err := s.Raw(query, values...).QueryRows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var indexes []dialect.Index
+	for rows.Next() {
+		var idx dialect.Index
+		var column string
+		if err := rows.Scan(&idx.Name, &idx.Unique, &idx.Implicit, &column); err != nil {
+			return nil, err
+		}
+		if n := len(indexes); n > 0 && indexes[n-1].Name == idx.Name {
+			indexes[n-1].Columns = append(indexes[n-1].Columns, column)
+			continue
+		}
+		idx.Columns = []string{column}
+		indexes = append(indexes, idx)
+	}
+	return indexes, rows.Err()
+}
+
+// Tables 查询数据库中全部用户表的表名
+func (s *Session) Tables() ([]string, error) {
+	query, values := s.dial.TablesSQL()
+	if query == "" {
+		return nil, dialect.ErrNotSupported
+	}
+	rows, err := s.Raw(query, values...).QueryRows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+// ForeignKeys 查询数据库中表 table 上的外键
+func (s *Session) ForeignKeys(table string) ([]dialect.ForeignKey, error) {
+	query, values := s.dial.ForeignKeysSQL(table)
+	if query == "" {
+		return nil, dialect.ErrNotSupported
+	}
+	rows, err := s.Raw(query, values...).QueryRows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []dialect.ForeignKey
+	last := -1
+	for rows.Next() {
+		var id int
+		var fk dialect.ForeignKey
+		var column, refColumn string
+		if err := rows.Scan(&id, &column, &fk.RefTable, &refColumn, &fk.OnUpdate, &fk.OnDelete); err != nil {
+			return nil, err
+		}
+		if n := len(keys); n > 0 && id == last {
+			keys[n-1].Columns = append(keys[n-1].Columns, column)
+			keys[n-1].RefColumns = append(keys[n-1].RefColumns, refColumn)
+			continue
+		}
+		fk.Columns, fk.RefColumns = []string{column}, []string{refColumn}
+		keys = append(keys, fk)
+		last = id
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,96 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"geeorm/clause"
+	"geeorm/schema"
+	"reflect"
+)
+
+// Get 根据主键查询一条记录并写入 value，联合主键的值按主键字段的声明顺序传入
+func (s *Session) Get(value interface{}, keys ...interface{}) error {
+	if err := s.Model(value).modelErr(); err != nil {
+		return err
+	}
+	table := s.GetrefTable()
+	if len(table.PrimaryFields) == 0 {
+		s.Clear()
+		return errors.New("primary key of " + table.Name + " is not declared")
+	}
+	if len(keys) != len(table.PrimaryFields) {
+		s.Clear()
+		return fmt.Errorf("expect %d primary key values of %s, but got %d", len(table.PrimaryFields), table.Name, len(keys))
+	}
+	for i, field := range table.PrimaryFields {
+		s.Where(clause.Eq(field.Name, keys[i]))
+	}
+	return s.First(value)
+}
+
+// Save 根据主键保存对象，主键为零值时插入，否则在事务中按主键检查记录是否存在，存在时更新全部字段，不存在时插入
+// 模型含有版本号列且版本号已过期时返回 ErrStaleObject；已软删除的记录同样被更新，但保持软删除状态
+func (s *Session) Save(value interface{}) (int64, error) {
+	if err := s.Model(value).modelErr(); err != nil {
+		return 0, err
+	}
+	table := s.GetrefTable()
+	if len(table.PrimaryFields) == 0 {
+		s.Clear()
+		return 0, errors.New("primary key of " + table.Name + " is not declared")
+	}
+	dest := reflect.Indirect(reflect.ValueOf(value))
+	if zeroKey(table, dest) {
+		return s.Insert(value)
+	}
+	cond, err := primaryKeyCondition(table, dest)
+	if err != nil {
+		s.Clear()
+		return 0, err
+	}
+	// 查询会清空链式调用的状态，先记录更新与插入时仍需使用的表名
+	name := s.table
+	result, err := s.Transaction(func(s *Session) (interface{}, error) {
+		count, err := s.Unscoped().Where(cond).Count()
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return s.Table(name).Unscoped().updateModel(value)
+		}
+		return s.Table(name).Insert(value)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int64), nil
+}
+
+// DeleteModel 根据对象的主键删除对应的记录，模型含有软删除标记列时执行软删除
+func (s *Session) DeleteModel(value interface{}) (int64, error) {
+	if err := s.Model(value).modelErr(); err != nil {
+		return 0, err
+	}
+	table := s.GetrefTable()
+	dest := reflect.Indirect(reflect.ValueOf(value))
+	cond, err := primaryKeyCondition(table, dest)
+	if err == nil && zeroKey(table, dest) {
+		err = errors.New("primary key of " + table.Name + " is zero")
+	}
+	if err != nil {
+		s.Clear()
+		return 0, err
+	}
+	return s.Where(cond).Delete()
+}
+
+// zeroKey 判断对象的主键字段是否均为零值
+func zeroKey(table *schema.Schema, dest reflect.Value) bool {
+	for _, field := range table.PrimaryFields {
+		f := dest.FieldByName(field.GoName)
+		if !reflect.DeepEqual(f.Interface(), reflect.Zero(f.Type()).Interface()) {
+			return false
+		}
+	}
+	return true
+}
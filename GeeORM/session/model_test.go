@@ -0,0 +1,94 @@
+package session
+
+import "testing"
+
+type Enrollment struct {
+	StudentID int `geeorm:"primaryKey"`
+	CourseID  int `geeorm:"primaryKey"`
+	Grade     string
+}
+
+func TestSession_SaveGetDeleteModel(t *testing.T) {
+	s := NewSession().Model(&Enrollment{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+
+	e := &Enrollment{StudentID: 1, CourseID: 2, Grade: "B"}
+	if _, err := s.Save(e); err != nil {
+		t.Fatal("failed to insert by Save", err)
+	}
+	e.Grade = "A"
+	if n, err := s.Save(e); err != nil || n != 1 {
+		t.Fatal("failed to update by Save", n, err)
+	}
+	if _, err := s.Save(&Enrollment{StudentID: 1, CourseID: 3, Grade: "C"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got Enrollment
+	if err := s.Get(&got, 1, 2); err != nil || got.Grade != "A" {
+		t.Fatal("failed to get by composite key", got, err)
+	}
+	if err := s.Get(&got, 1); err == nil {
+		t.Fatal("expect error for missing key values")
+	}
+	if n, err := s.DeleteModel(e); err != nil || n != 1 {
+		t.Fatal("failed to delete by model", n, err)
+	}
+	if err := s.Get(&got, 1, 2); err == nil {
+		t.Fatal("expect record to be deleted")
+	}
+	if count, _ := s.Count(); count != 1 {
+		t.Fatal("expect 1 remaining record, but got", count)
+	}
+	if _, err := s.DeleteModel(&Enrollment{}); err == nil {
+		t.Fatal("expect error when deleting with zero primary key")
+	}
+}
+
+func TestSession_SaveVersioned(t *testing.T) {
+	s := NewSession().Model(&Wallet{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+
+	w := &Wallet{ID: 1, Balance: 10}
+	if _, err := s.Save(w); err != nil {
+		t.Fatal("failed to insert by Save", err)
+	}
+	if _, err := s.Save(w); err != nil || w.Version != 1 {
+		t.Fatal("failed to save unchanged record", w, err)
+	}
+	stale := &Wallet{ID: 1, Balance: 20}
+	if _, err := s.Save(stale); err != ErrStaleObject {
+		t.Fatal("expect ErrStaleObject, but got", err)
+	}
+	if count, _ := s.Model(&Wallet{}).Count(); count != 1 {
+		t.Fatal("expect 1 record, but got", count)
+	}
+}
+
+type NoPK struct {
+	Code string
+}
+
+func TestSession_SaveEdgeCases(t *testing.T) {
+	s := NewSession().Model(&NoPK{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	if _, err := s.Save(&NoPK{Code: "a"}); err == nil {
+		t.Fatal("expect error for model without primary key")
+	}
+
+	s = NewSession().Model(&Article{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	_, _ = s.Insert(&Article{ID: 1, Title: "Go"})
+	_, _ = s.Where("ID = ?", 1).Delete()
+	if n, err := s.Save(&Article{ID: 1, Title: "ORM"}); err != nil || n != 1 {
+		t.Fatal("failed to save soft deleted record", n, err)
+	}
+	var articles []Article
+	if err := s.Unscoped().Find(&articles); err != nil || len(articles) != 1 || articles[0].Title != "ORM" || articles[0].DeletedAt == nil {
+		t.Fatal("expect soft deleted record to be updated, got", articles, err)
+	}
+}
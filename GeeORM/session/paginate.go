@@ -0,0 +1,161 @@
+package session
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"geeorm/clause"
+	"geeorm/schema"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// 游标中的键值以 interface{} 编码，除基础类型外需要注册具体类型
+func init() {
+	gob.Register(time.Time{})
+}
+
+// paginationKey 分页的排序键
+type paginationKey struct {
+	field *schema.Field
+	desc  bool
+}
+
+// Paginate 基于键集的分页查询，按排序键的顺序读取 cursor 之后的 pageSize 行并追加到 dest
+// keys 为排序键的列名，可以带 DESC 后缀表示降序，未指定时按主键升序，排序键的组合必须唯一
+// 还有下一页时返回不透明的游标 next，将其传回即可读取下一页；cursor 为空时读取第一页
+// 其他查询条件与 Find 相同，但排序与行数由 Paginate 决定
+func (s *Session) Paginate(dest interface{}, cursor string, pageSize int, keys ...string) (next string, hasMore bool, err error) {
+	destSlice := reflect.Indirect(reflect.ValueOf(dest))
+	elemType := destSlice.Type().Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if err = s.Model(reflect.New(elemType).Elem().Interface()).modelErr(); err != nil {
+		return "", false, err
+	}
+	table := s.GetrefTable()
+	orders, err := paginationKeys(table, keys)
+	if err == nil && pageSize <= 0 {
+		err = errors.New("page size must be positive")
+	}
+	var values []interface{}
+	if err == nil && cursor != "" {
+		if values, err = decodeCursor(cursor); err == nil && len(values) != len(orders) {
+			err = errors.New("cursor does not match the pagination keys")
+		}
+	}
+	if err != nil {
+		s.Clear()
+		return "", false, err
+	}
+	if values != nil {
+		s.Where(keysetCondition(orders, values))
+	}
+	var orderBy []string
+	for _, key := range orders {
+		dir := "ASC"
+		if key.desc {
+			dir = "DESC"
+		}
+		orderBy = append(orderBy, s.dial.Quote(key.field.Name)+" "+dir)
+	}
+
+	start := destSlice.Len()
+	// 多读取一行用来判断是否还有下一页
+	if err := s.Orderby(strings.Join(orderBy, ", ")).Limit(pageSize + 1).Find(dest); err != nil {
+		return "", false, err
+	}
+	if destSlice.Len()-start <= pageSize {
+		return "", false, nil
+	}
+	destSlice.Set(destSlice.Slice(0, start+pageSize))
+	last := reflect.Indirect(destSlice.Index(destSlice.Len() - 1))
+	values = values[:0]
+	for _, key := range orders {
+		values = append(values, last.FieldByName(key.field.GoName).Interface())
+	}
+	if next, err = encodeCursor(values); err != nil {
+		return "", false, err
+	}
+	return next, true, nil
+}
+
+// paginationKeys 解析排序键，未指定时使用主键
+func paginationKeys(table *schema.Schema, keys []string) ([]paginationKey, error) {
+	var orders []paginationKey
+	if len(keys) == 0 {
+		for _, field := range table.PrimaryFields {
+			orders = append(orders, paginationKey{field: field})
+		}
+		if len(orders) == 0 {
+			return nil, errors.New("primary key of " + table.Name + " is not declared")
+		}
+		return orders, nil
+	}
+	for _, key := range keys {
+		parts := strings.Fields(key)
+		if len(parts) == 0 || len(parts) > 2 || len(parts) == 2 && !strings.EqualFold(parts[1], "ASC") && !strings.EqualFold(parts[1], "DESC") {
+			return nil, fmt.Errorf("invalid pagination key %q", key)
+		}
+		field := lookUpColumn(table, parts[0])
+		if field == nil {
+			return nil, fmt.Errorf("column %s is not a field of %s", parts[0], table.Name)
+		}
+		orders = append(orders, paginationKey{field: field, desc: len(parts) == 2 && strings.EqualFold(parts[1], "DESC")})
+	}
+	return orders, nil
+}
+
+// keysetCondition 构造位于游标之后的条件，例如 (a > ?) OR (a = ? AND b > ?)
+func keysetCondition(orders []paginationKey, values []interface{}) clause.Expression {
+	var conds []clause.Expression
+	for i, key := range orders {
+		var and []clause.Expression
+		for j := 0; j < i; j++ {
+			and = append(and, clause.Eq(orders[j].field.Name, values[j]))
+		}
+		if key.desc {
+			and = append(and, clause.Lt(key.field.Name, values[i]))
+		} else {
+			and = append(and, clause.Gt(key.field.Name, values[i]))
+		}
+		conds = append(conds, clause.And(and...))
+	}
+	return clause.Or(conds...)
+}
+
+// encodeCursor 将排序键的值编码为可以放入URL的游标
+func encodeCursor(values []interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(values); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeCursor 从游标中解码排序键的值
+func decodeCursor(cursor string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %v", err)
+	}
+	var values []interface{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %v", err)
+	}
+	return values, nil
+}
+
+// lookUpColumn 根据列名获取字段，不存在时返回 nil
+func lookUpColumn(table *schema.Schema, column string) *schema.Field {
+	for _, field := range table.Fields {
+		if field.Name == column {
+			return field
+		}
+	}
+	return nil
+}
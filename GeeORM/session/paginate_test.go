@@ -0,0 +1,86 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+type Comment struct {
+	ID       int `geeorm:"primaryKey"`
+	Score    int
+	PostedAt time.Time
+}
+
+func TestSession_Paginate(t *testing.T) {
+	s := NewSession().Model(&Comment{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	comments := make([]Comment, 25)
+	for i := range comments {
+		comments[i] = Comment{ID: i + 1, Score: i % 3, PostedAt: base.Add(time.Duration(i/2) * time.Hour)}
+	}
+	if _, err := s.InsertBatch(comments, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var all []Comment
+	cursor, pages := "", 0
+	for {
+		var page []Comment
+		next, hasMore, err := s.Paginate(&page, cursor, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		all = append(all, page...)
+		if !hasMore {
+			break
+		}
+		cursor = next
+	}
+	if pages != 3 || len(all) != 25 || all[24].ID != 25 {
+		t.Fatal("failed to paginate by primary key", pages, len(all))
+	}
+
+	// 按 Score 降序、发布时间及主键升序分页，每页之间不重复也不遗漏
+	seen := make(map[int]bool)
+	cursor = ""
+	for {
+		var page []Comment
+		next, hasMore, err := s.Where("Score > ?", 0).Paginate(&page, cursor, 4, "Score DESC", "PostedAt", "ID")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range page {
+			if seen[c.ID] || c.Score == 0 {
+				t.Fatal("unexpected comment", c)
+			}
+			seen[c.ID] = true
+		}
+		if !hasMore {
+			break
+		}
+		cursor = next
+	}
+	if len(seen) != 16 {
+		t.Fatal("expect 16 comments, but got", len(seen))
+	}
+
+	var ptrs []*Comment
+	next, hasMore, err := s.Paginate(&ptrs, "", 10)
+	if err != nil || !hasMore || len(ptrs) != 10 || ptrs[9].ID != 10 {
+		t.Fatal("failed to paginate into pointers", len(ptrs), err)
+	}
+	if _, _, err := s.Paginate(&ptrs, next, 10); err != nil || len(ptrs) != 20 || ptrs[19].ID != 20 {
+		t.Fatal("failed to paginate into pointers", len(ptrs), err)
+	}
+
+	var page []Comment
+	if _, _, err := s.Paginate(&page, "not a cursor", 10); err == nil {
+		t.Fatal("expect error for invalid cursor")
+	}
+	if _, _, err := s.Paginate(&page, "", 10, "Unknown"); err == nil {
+		t.Fatal("expect error for unknown key")
+	}
+}
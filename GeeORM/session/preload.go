@@ -0,0 +1,196 @@
+package session
+
+import (
+	"fmt"
+	"geeorm/clause"
+	"geeorm/schema"
+	"reflect"
+	"strings"
+)
+
+// Preload 在Find/First查询完成后批量预加载给定的关联字段，支持 "Orders.Items" 形式的嵌套关联
+func (s *Session) Preload(name string) *Session {
+	s.preloads = append(s.preloads, name)
+	return s
+}
+
+// preload 为查询结果加载关联对象，每一层关联只发起一次 WHERE fk IN (...) 查询
+func (s *Session) preload(table *schema.Schema, dest reflect.Value, path string) error {
+	if dest.Len() == 0 {
+		return nil
+	}
+	names := strings.SplitN(path, ".", 2)
+	rel := table.GetRelationship(names[0])
+	if rel == nil {
+		return fmt.Errorf("relationship %s is not exists in %s", names[0], table.Name)
+	}
+	relTable, err := s.parseType(rel.Type)
+	if err != nil {
+		return err
+	}
+	if rel.Kind == schema.ManyToMany {
+		return s.preloadMany2Many(table, relTable, rel, dest, names)
+	}
+
+	// ownerKey 为本对象上参与关联的字段，relKey 为关联对象上参与关联的字段
+	ownerKey, relKey := rel.References, rel.ForeignKey
+	if rel.Kind == schema.BelongsTo {
+		ownerKey, relKey = rel.ForeignKey, rel.References
+	}
+	column := relTable.LookUpField(relKey)
+	if column == nil {
+		return fmt.Errorf("field %s is not exists in %s", relKey, relTable.Name)
+	}
+	keys := collectKeys(dest, ownerKey)
+	if len(keys) == 0 {
+		return nil
+	}
+	related := reflect.New(reflect.SliceOf(rel.Type))
+	if err := s.fork().Where(clause.In(column.Name, keys...)).Find(related.Interface()); err != nil {
+		return err
+	}
+	related = related.Elem()
+	if len(names) == 2 {
+		if err := s.preload(relTable, related, names[1]); err != nil {
+			return err
+		}
+	}
+
+	groups := make(map[string][]reflect.Value)
+	for i := 0; i < related.Len(); i++ {
+		if key, ok := fieldKey(related.Index(i), relKey); ok {
+			groups[key] = append(groups[key], related.Index(i))
+		}
+	}
+	for i := 0; i < dest.Len(); i++ {
+		owner := reflect.Indirect(dest.Index(i))
+		if key, ok := fieldKey(owner, ownerKey); ok {
+			setRelation(owner.FieldByName(rel.Name), groups[key])
+		}
+	}
+	return nil
+}
+
+// fieldKey 返回对象中某个字段值的字符串形式，用于匹配不同整数类型的主外键，字段为nil指针时返回false
+func fieldKey(v reflect.Value, name string) (string, bool) {
+	f := reflect.Indirect(v).FieldByName(name)
+	if f.Kind() == reflect.Ptr {
+		if f.IsNil() {
+			return "", false
+		}
+		f = f.Elem()
+	}
+	return fmt.Sprint(f.Interface()), true
+}
+
+// collectKeys 收集一组对象中某个字段去重后的值
+func collectKeys(dest reflect.Value, name string) []interface{} {
+	var keys []interface{}
+	seen := make(map[string]bool)
+	for i := 0; i < dest.Len(); i++ {
+		key, ok := fieldKey(dest.Index(i), name)
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, reflect.Indirect(reflect.Indirect(dest.Index(i)).FieldByName(name)).Interface())
+	}
+	return keys
+}
+
+// setRelation 将加载到的关联对象写入关联字段，字段可以是结构体、结构体指针或它们的切片
+func setRelation(field reflect.Value, values []reflect.Value) {
+	switch field.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(field.Type(), 0, len(values))
+		for _, v := range values {
+			if field.Type().Elem().Kind() == reflect.Ptr {
+				v = v.Addr()
+			}
+			slice = reflect.Append(slice, v)
+		}
+		field.Set(slice)
+	case reflect.Ptr:
+		if len(values) > 0 {
+			field.Set(values[0].Addr())
+		}
+	default:
+		if len(values) > 0 {
+			field.Set(values[0])
+		}
+	}
+}
+
+// preloadMany2Many 先查询连接表得到关联关系，再批量查询关联对象
+func (s *Session) preloadMany2Many(table, relTable *schema.Schema, rel *schema.Relationship, dest reflect.Value, names []string) error {
+	column := relTable.LookUpField(rel.References)
+	if column == nil {
+		return fmt.Errorf("field %s is not exists in %s", rel.References, relTable.Name)
+	}
+	keys := collectKeys(dest, rel.ForeignKey)
+	if len(keys) == 0 {
+		return nil
+	}
+	join := s.fork()
+	join.clause.Set(clause.SELECT, rel.JoinTable, []string{rel.JoinForeignKey, rel.JoinReferences})
+	join.Where(clause.In(rel.JoinForeignKey, keys...))
+	sql, vars := join.clause.Build(clause.SELECT, clause.WHERE)
+	rows, err := join.Raw(sql, vars...).QueryRows()
+	if err != nil {
+		return err
+	}
+	ownerType := reflect.Indirect(dest.Index(0)).FieldByName(rel.ForeignKey).Type()
+	refType, _ := rel.Type.FieldByName(rel.References)
+	links := make(map[string][]string)
+	var refs []interface{}
+	seen := make(map[string]bool)
+	for rows.Next() {
+		owner, ref := reflect.New(ownerType), reflect.New(refType.Type)
+		if err := rows.Scan(owner.Interface(), ref.Interface()); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		ownerKey, refKey := fmt.Sprint(owner.Elem().Interface()), fmt.Sprint(ref.Elem().Interface())
+		links[ownerKey] = append(links[ownerKey], refKey)
+		if !seen[refKey] {
+			seen[refKey] = true
+			refs = append(refs, ref.Elem().Interface())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	if err := rows.Close(); err != nil || len(refs) == 0 {
+		return err
+	}
+
+	related := reflect.New(reflect.SliceOf(rel.Type))
+	if err := s.fork().Where(clause.In(column.Name, refs...)).Find(related.Interface()); err != nil {
+		return err
+	}
+	related = related.Elem()
+	if len(names) == 2 {
+		if err := s.preload(relTable, related, names[1]); err != nil {
+			return err
+		}
+	}
+	byKey := make(map[string]reflect.Value)
+	for i := 0; i < related.Len(); i++ {
+		if key, ok := fieldKey(related.Index(i), rel.References); ok {
+			byKey[key] = related.Index(i)
+		}
+	}
+	for i := 0; i < dest.Len(); i++ {
+		owner := reflect.Indirect(dest.Index(i))
+		key, _ := fieldKey(owner, rel.ForeignKey)
+		var values []reflect.Value
+		for _, refKey := range links[key] {
+			if v, ok := byKey[refKey]; ok {
+				values = append(values, v)
+			}
+		}
+		setRelation(owner.FieldByName(rel.Name), values)
+	}
+	return nil
+}
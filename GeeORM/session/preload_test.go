@@ -0,0 +1,90 @@
+package session
+
+import "testing"
+
+type Customer struct {
+	ID      int `geeorm:"primaryKey"`
+	Name    string
+	Orders  []Order
+	Profile *Profile
+}
+
+type Order struct {
+	ID         int `geeorm:"primaryKey"`
+	CustomerID int64
+	Amount     float64
+	Customer   Customer
+}
+
+type Profile struct {
+	ID    int `geeorm:"primaryKey"`
+	Owner int
+	Bio   string
+}
+
+func testPreloadInit(t *testing.T) *Session {
+	t.Helper()
+	s := NewSession()
+	for _, model := range []interface{}{&Customer{}, &Order{}, &Profile{}} {
+		if err := s.Model(model).DropTable(); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Model(model).CreateTable(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err1 := s.Insert(&Customer{ID: 1, Name: "Tom"}, &Customer{ID: 2, Name: "Sam"})
+	_, err2 := s.Insert(&Order{ID: 1, CustomerID: 1, Amount: 10}, &Order{ID: 2, CustomerID: 1, Amount: 20},
+		&Order{ID: 3, CustomerID: 2, Amount: 30})
+	_, err3 := s.Insert(&Profile{ID: 1, Owner: 2, Bio: "hello"})
+	if err1 != nil || err2 != nil || err3 != nil {
+		t.Fatal("failed init test records")
+	}
+	return s
+}
+
+func TestSession_Preload(t *testing.T) {
+	s := testPreloadInit(t)
+	var customers []Customer
+	if err := s.Preload("Orders").Orderby("ID").Find(&customers); err != nil || len(customers) != 2 {
+		t.Fatal("failed to query customers", err)
+	}
+	if len(customers[0].Orders) != 2 || len(customers[1].Orders) != 1 || customers[1].Orders[0].Amount != 30 {
+		t.Fatal("failed to preload has many relationship, got", customers)
+	}
+
+	var orders []Order
+	if err := s.Preload("Customer").Find(&orders); err != nil || len(orders) != 3 {
+		t.Fatal("failed to query orders", err)
+	}
+	if orders[0].Customer.Name != "Tom" || orders[2].Customer.Name != "Sam" {
+		t.Fatal("failed to preload belongs to relationship, got", orders)
+	}
+}
+
+type Member struct {
+	ID      int      `geeorm:"primaryKey"`
+	Profile *Profile `geeorm:"foreignKey:Owner"`
+	Orders  []Order  `geeorm:"foreignKey:CustomerID"`
+}
+
+func TestSession_PreloadNested(t *testing.T) {
+	s := testPreloadInit(t)
+	_ = s.Model(&Member{}).DropTable()
+	_ = s.Model(&Member{}).CreateTable()
+	_, _ = s.Insert(&Member{ID: 1}, &Member{ID: 2})
+
+	m := &Member{}
+	if err := s.Preload("Profile").Preload("Orders.Customer").Where("ID = ?", 2).First(m); err != nil {
+		t.Fatal("failed to query member", err)
+	}
+	if m.Profile == nil || m.Profile.Bio != "hello" {
+		t.Fatal("failed to preload has one relationship, got", m.Profile)
+	}
+	if len(m.Orders) != 1 || m.Orders[0].Customer.Name != "Sam" {
+		t.Fatal("failed to preload nested relationship, got", m.Orders)
+	}
+	if err := s.Preload("Unknown").First(m); err == nil {
+		t.Fatal("expect error of unknown relationship")
+	}
+}
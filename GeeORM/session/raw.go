@@ -1,12 +1,14 @@
 package session
 
 import (
+	"context"
 	"database/sql"
 	"geeorm/clause"
 	"geeorm/dialect"
 	"geeorm/log"
 	"geeorm/schema"
 	"strings"
+	"time"
 )
 
 // Session 数据库访问会话
@@ -18,6 +20,21 @@ type Session struct {
 	sql strings.Builder // 数据库操作语句
 	sqlVars []interface{} // 数据库操作占位符对应的参数
 	clause clause.Clause
+	ctx context.Context // 会话中所有数据库操作使用的上下文
+	savepoints int // 当前嵌套事务的保存点深度
+	where clause.Expression // 链式调用中累积的查询条件
+	preloads []string // 查询完成后需要预加载的关联字段
+	unscoped bool // 是否忽略软删除条件
+	nowFunc func() time.Time // 获取当前时间的函数，为nil时使用 time.Now
+	naming schema.NamingStrategy // 解析模型时使用的命名策略
+	table string // 链式调用中指定的表名，为空时使用模型对应的表名
+	conflict *clause.OnConflict // 插入冲突时的处理方式
+	progress BatchProgress // 批量插入的进度回调
+	selects []string // 链式调用中指定查询的列
+	distinct bool // 是否去除重复的行
+	having clause.Expression // 链式调用中累积的分组过滤条件
+	modelSet bool // 链式调用中是否通过 Model 指定了模型
+	err error // 链式调用中解析模型时发生的错误
 }
 
 var _ CommonDB = (*sql.DB)(nil)
@@ -25,9 +42,9 @@ var _ CommonDB = (*sql.Tx)(nil)
 
 // CommonDB 数据库操作的的最小化实现
 type CommonDB interface {
-	Query(query string, args ...interface{}) (*sql.Rows, error)
-	QueryRow(query string, args ...interface{}) *sql.Row
-	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 }
 
 // New 用于创建一个新的数据库访问会话
@@ -35,6 +52,7 @@ func New(db *sql.DB, dial dialect.Dialect) *Session {
 	return &Session{
 		db: db,
 		dial: dial,
+		clause: clause.New(dial),
 	}
 } 
 
@@ -42,7 +60,45 @@ func New(db *sql.DB, dial dialect.Dialect) *Session {
 func (sess *Session) Clear() {
 	sess.sql.Reset()
 	sess.sqlVars = nil
-	sess.clause = clause.Clause{}
+	sess.clause = clause.New(sess.dial)
+	sess.where = nil
+	sess.preloads = nil
+	sess.unscoped = false
+	sess.table = ""
+	sess.conflict = nil
+	sess.progress = nil
+	sess.selects = nil
+	sess.distinct = false
+	sess.having = nil
+	sess.modelSet = false
+	sess.err = nil
+}
+
+// WithContext 设置会话使用的上下文，之后的查询、执行及事务都会受其取消与超时控制
+func (sess *Session) WithContext(ctx context.Context) *Session {
+	sess.ctx = ctx
+	return sess
+}
+
+// Context 返回会话使用的上下文，未设置时返回 context.Background()
+func (sess *Session) Context() context.Context {
+	if sess.ctx == nil {
+		return context.Background()
+	}
+	return sess.ctx
+}
+
+// SetNamingStrategy 设置会话解析模型时使用的命名策略，应在设置模型之前调用
+func (sess *Session) SetNamingStrategy(naming schema.NamingStrategy) *Session {
+	sess.naming = naming
+	return sess
+}
+
+// fork 创建一个共享数据库连接、事务与上下文的新会话，用于在一次操作中执行额外的语句
+func (sess *Session) fork() *Session {
+	s := New(sess.db, sess.dial)
+	s.tx, s.ctx, s.nowFunc, s.naming = sess.tx, sess.ctx, sess.nowFunc, sess.naming
+	return s
 }
 
 // DB 返回会话的数据库指针
@@ -53,7 +109,7 @@ func (sess *Session) DB() CommonDB {
 	return sess.db
 }
 
-// Raw 构建数据库访问原始请求
+// Raw 构建数据库访问原始请求，语句中的参数统一使用 ? 占位符，执行时替换为方言对应的占位符
 func (sess *Session) Raw(sql string, sqlVars ...interface{}) *Session {
 	sess.sql.WriteString(sql)
 	sess.sql.WriteString(" ")
@@ -61,11 +117,17 @@ func (sess *Session) Raw(sql string, sqlVars ...interface{}) *Session {
 	return sess
 }
 
+// query 返回将 ? 占位符替换为方言占位符后的完整语句
+func (sess *Session) query() string {
+	return dialect.Rebind(sess.dial, sess.sql.String())
+}
+
 // Exec 数据库原始Exec操作
 func (sess *Session) Exec() (result sql.Result, err error) {
 	defer sess.Clear()
-	log.Info(sess.sql.String(), sess.sqlVars)
-	if result, err = sess.DB().Exec(sess.sql.String(), sess.sqlVars...); err != nil {
+	query := sess.query()
+	log.Info(query, sess.sqlVars)
+	if result, err = sess.DB().ExecContext(sess.Context(), query, sess.sqlVars...); err != nil {
 		log.Error(err)
 	}
 	return result, err
@@ -74,15 +136,17 @@ func (sess *Session) Exec() (result sql.Result, err error) {
 // QueryRow 数据库查询一行QueryRaw操作
 func (sess *Session) QueryRow() (*sql.Row) {
 	defer sess.Clear()
-	log.Info(sess.sql.String(), sess.sqlVars)
-	return sess.DB().QueryRow(sess.sql.String(), sess.sqlVars...)
+	query := sess.query()
+	log.Info(query, sess.sqlVars)
+	return sess.DB().QueryRowContext(sess.Context(), query, sess.sqlVars...)
 }
 
 // QueryRows 数据库查询多行QueryRaws操作
 func (sess *Session) QueryRows() (*sql.Rows, error) {
 	defer sess.Clear()
-	log.Info(sess.sql.String(), sess.sqlVars)
-	rows, err := sess.DB().Query(sess.sql.String(), sess.sqlVars...)
+	query := sess.query()
+	log.Info(query, sess.sqlVars)
+	rows, err := sess.DB().QueryContext(sess.Context(), query, sess.sqlVars...)
 	if err != nil {
 		log.Error(err)
 	}
@@ -1,7 +1,9 @@
 package session
 
 import (
+	"context"
 	"database/sql"
+	"geeorm/clause"
 	"geeorm/dialect"
 	"os"
 	"testing"
@@ -45,4 +47,35 @@ func TestSession_QueryRows(t *testing.T) {
 	if err := row.Scan(&count); err != nil  || count != 2 {
 		t.Fatal("failed to query row", err)
 	}
-}
\ No newline at end of file
+}
+
+func TestSession_WithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := NewSession().WithContext(ctx)
+	if _, err := s.Raw("SELECT 1").Exec(); err != context.Canceled {
+		t.Fatal("expect context canceled, but got", err)
+	}
+	if _, err := s.Raw("SELECT 1").QueryRows(); err != context.Canceled {
+		t.Fatal("expect context canceled, but got", err)
+	}
+	if err := s.Begin(); err != context.Canceled {
+		t.Fatal("expect context canceled, but got", err)
+	}
+}
+
+func TestSession_RawRebind(t *testing.T) {
+	dial, _ := dialect.GetDialect("postgres")
+	s := New(TestDB, dial).Raw("SELECT * FROM \"User\" WHERE Name = ?", "Tom").Raw("AND Note <> '?' AND Age > ?", 18)
+	if query := s.query(); query != `SELECT * FROM "User" WHERE Name = $1 AND Note <> '?' AND Age > $2 ` {
+		t.Fatal("failed to rebind raw sql, got", query)
+	}
+
+	// 子句构造的语句与追加的原始语句统一编号
+	s = New(TestDB, dial).Where("Name = ?", "Tom").Or("ID > ?", 1)
+	sql, vars := s.clause.Build(clause.WHERE)
+	s.Raw("SELECT * FROM \"User\" "+sql, vars...).Raw("AND z = ?", 2)
+	if query := s.query(); query != `SELECT * FROM "User" WHERE (Name = $1) OR (ID > $2) AND z = $3 ` {
+		t.Fatal("failed to number placeholders, got", query)
+	}
+}
@@ -1,89 +1,270 @@
 package session
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"geeorm/clause"
+	"geeorm/dialect"
+	"geeorm/schema"
+	"math"
 	"reflect"
+	"strings"
 )
 
-// Insert INSERT的外部调用方法，可以直接将对象插入数据库
+// Insert INSERT的外部调用方法，可以直接将对象插入数据库，设置了 OnConflict 时按其处理主键或唯一约束冲突
+// 模型含有自增列且插入的对象中自增列均为零值时由数据库生成自增值，并写回传入的对象指针
+// 没有传入对象时不执行任何语句
 func (s *Session) Insert(values ...interface{}) (int64, error) {
+	if len(values) == 0 {
+		s.Clear()
+		return 0, nil
+	}
+	var table *schema.Schema
+	for _, value := range values {
+		if err := s.CallMethod(BeforeInsert, value); err != nil {
+			s.Clear()
+			return 0, err
+		}
+		if err := s.Model(value).modelErr(); err != nil {
+			return 0, err
+		}
+		table = s.GetrefTable()
+		s.setCreateTimestamps(table, value)
+	}
+	auto := generatedField(table, values)
 	recordValues := make([]interface{}, 0)
 	for _, value := range values {
-		s.CallMethod(BeforeInsert, value)
-		table := s.Model(value).GetrefTable()
-		s.clause.Set(clause.INSERT, table.Name, table.FieldNames)
-		recordValues = append(recordValues, table.RecordValues(value))
+		recordValues = append(recordValues, insertValues(table, auto, value))
 	}
+	s.clause.Set(clause.INSERT, s.tableName(), insertColumns(table, auto))
 	s.clause.Set(clause.VALUES, recordValues...)
-	sql, vars := s.clause.Build(clause.INSERT, clause.VALUES)
-	reslut, err := s.Raw(sql, vars...).Exec()
+	orders := []clause.Type{clause.INSERT, clause.VALUES}
+	if s.conflict != nil {
+		// 冲突的行不会生成自增值，无法将自增值与对象一一对应
+		s.clause.Set(clause.ONCONFLICT, s.onConflict(table))
+		orders, auto = append(orders, clause.ONCONFLICT), nil
+		if sql, _ := s.clause.Build(clause.ONCONFLICT); sql == "" {
+			// 方言无法表达该冲突处理时不能退化为普通的插入
+			s.Clear()
+			return 0, dialect.ErrNotSupported
+		}
+	}
+	var affected int64
+	var err error
+	if auto != nil && s.dial.ReturningSQL(auto.Name) != "" {
+		s.clause.Set(clause.RETURNING, auto.Name)
+		sql, vars := s.clause.Build(append(orders, clause.RETURNING)...)
+		affected, err = s.insertReturning(sql, vars, auto, values)
+	} else if auto != nil && len(values) > 1 && s.dial.LastInsertIDIsFirst() {
+		affected, err = s.insertEach(orders, recordValues, auto, values)
+	} else {
+		sql, vars := s.clause.Build(orders...)
+		affected, err = s.insertExec(sql, vars, auto, values)
+	}
 	if err != nil {
 		return 0, err
 	}
-	return reslut.RowsAffected()
+	for _, value := range values {
+		if err := s.CallMethod(AfterInsert, value); err != nil {
+			return 0, err
+		}
+	}
+	return affected, nil
 }
 
-// Find 查找操作的外部接口
+// Find 查找操作的外部接口，查询结果按列名写入切片元素，无法匹配的列被忽略
+// 本次链式调用未通过 Model 指定模型时，结构体元素即为模型，查询其对应的表(或 Table 指定的表名)并执行钩子与预加载
+// 元素为 map[string]interface{}，或与 Model 指定的模型不同的结构体时，查询指定的表，将结果写入元素而不执行模型的钩子与预加载
+// 元素可以是结构体指针
 func (s *Session) Find(value interface{}) error {
-	s.CallMethod(BeforeQuery, nil)
 	destSlice := reflect.Indirect(reflect.ValueOf(value))
-	destType := destSlice.Type().Elem()
-	table := s.Model(reflect.New(destType).Elem().Interface()).GetrefTable()
-	s.clause.Set(clause.SELECT, table.Name, table.FieldNames)
-	sql, vars := s.clause.Build(clause.SELECT, clause.WHERE, clause.ORDERBY, clause.LIMIT)
-	rows, err := s.Raw(sql, vars...).QueryRows()
+	destType, isPtr := destSlice.Type().Elem(), false
+	if destType.Kind() == reflect.Ptr {
+		destType, isPtr = destType.Elem(), true
+	}
+	if !s.modelSet && destType.Kind() == reflect.Struct {
+		s.Model(reflect.New(destType).Elem().Interface())
+	}
+	if err := s.modelErr(); err != nil {
+		return err
+	}
+	if s.refTable == nil || destType != reflect.Indirect(reflect.ValueOf(s.refTable.Model)).Type() {
+		return s.findInto(value)
+	}
+	preloads := s.preloads
+	start := destSlice.Len()
+	table := s.refTable
+	rows, err := s.queryModel()
 	if err != nil {
 		return err
 	}
+	columns, err := rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		return err
+	}
+	scanner := &rowScanner{columns: columns, fields: fieldsByColumn(table.Fields, columns)}
 
 	for rows.Next() {
 		dest := reflect.New(destType).Elem()
-		var values []interface{}
-		for _, name := range table.FieldNames {
-			values = append(values, dest.FieldByName(name).Addr().Interface())
+		if err := scanner.scan(rows, dest); err != nil {
+			_ = rows.Close()
+			return err
 		}
-		if err := rows.Scan(values...); err != nil {
+		if err := s.CallMethod(AfterQuery, dest.Addr().Interface()); err != nil {
+			_ = rows.Close()
 			return err
 		}
-		s.CallMethod(AfterQuery, dest.Addr().Interface())
+		if isPtr {
+			dest = dest.Addr()
+		}
 		destSlice.Set(reflect.Append(destSlice, dest))
 	} 
-	return rows.Close()
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	for _, name := range preloads {
+		if err := s.preload(table, destSlice.Slice(start, destSlice.Len()), name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryModel 执行BeforeQuery钩子后查询当前模型，返回结果集
+func (s *Session) queryModel() (*sql.Rows, error) {
+	if err := s.CallMethod(BeforeQuery, nil); err != nil {
+		s.Clear()
+		return nil, err
+	}
+	columns, err := s.selectColumns()
+	if err != nil {
+		s.Clear()
+		return nil, err
+	}
+	s.softDeleteScope()
+	s.clause.Set(clause.SELECT, s.tableName(), columns, s.distinct)
+	sql, vars := s.buildQuery()
+	return s.Raw(sql, vars...).QueryRows()
+}
+
+// findInto 查询当前模型或 Table 指定的表，将结果按列名写入 value 指向的切片
+func (s *Session) findInto(value interface{}) error {
+	if s.refTable == nil && s.table == "" {
+		s.Clear()
+		return errors.New("Model or Table must be set to find into " + reflect.TypeOf(value).Elem().String())
+	}
+	columns, err := s.selectColumns()
+	if err != nil {
+		s.Clear()
+		return err
+	}
+	if s.refTable != nil {
+		s.softDeleteScope()
+	}
+	s.clause.Set(clause.SELECT, s.tableName(), columns, s.distinct)
+	sql, vars := s.buildQuery()
+	return s.Raw(sql, vars...).Scan(value)
 }
 
 // Update UPDATE操作外部接口, 可以实现自动识别输入格式，可以是map， 或者kv列表
+// 传入对象指针时根据主键更新对象的全部字段，模型含有版本号列时启用乐观锁
 func (s *Session) Update(kv ...interface{}) (int64, error) {
-	m, ok := kv[0].(map[string]interface{})
-	if !ok {
-		m = make(map[string]interface{})
+	if v := reflect.Indirect(reflect.ValueOf(kv[0])); v.Kind() == reflect.Struct {
+		return s.updateModel(kv[0])
+	}
+	if err := s.modelErr(); err != nil {
+		return 0, err
+	}
+	m := make(map[string]interface{})
+	if values, ok := kv[0].(map[string]interface{}); ok {
+		for k, v := range values {
+			m[k] = v
+		}
+	} else {
 		for i := 0; i < len(kv); i += 2 {
 			m[kv[i].(string)] = kv[i+1]
 		}
 	}
-	s.clause.Set(clause.UPDATE, s.GetrefTable().Name, m)
+	if err := s.CallMethod(BeforeUpdate, nil); err != nil {
+		s.Clear()
+		return 0, err
+	}
+	s.softDeleteScope()
+	serializeValues(s.GetrefTable(), m)
+	s.setUpdateTimestamps(s.GetrefTable(), m)
+	checked := s.versionScope(s.GetrefTable(), m)
+	s.clause.Set(clause.UPDATE, s.tableName(), m)
 	sql, vars := s.clause.Build(clause.UPDATE, clause.WHERE)
 	result, err := s.Raw(sql, vars...).Exec()
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if checked && affected == 0 {
+		return 0, ErrStaleObject
+	}
+	if err := s.CallMethod(AfterUpdate, nil); err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// serializeValues 对声明了序列化器的列的更新值编码
+func serializeValues(table *schema.Schema, m map[string]interface{}) {
+	for name, value := range m {
+		if field := lookUpColumn(table, name); field != nil && field.Serializer != "" && value != nil {
+			m[name] = field.ValueOf(reflect.ValueOf(value))
+		}
+	}
 }
 
 // Delete 删除操作外部接口
 func (s *Session) Delete() (int64, error) {
-	s.clause.Set(clause.DELETE, s.GetrefTable().Name)
-	sql, vars := s.clause.Build(clause.DELETE, clause.WHERE)
-	result, err := s.Raw(sql, vars...).Exec()
+	if err := s.modelErr(); err != nil {
+		return 0, err
+	}
+	if err := s.CallMethod(BeforeDelete, nil); err != nil {
+		s.Clear()
+		return 0, err
+	}
+	var result sql.Result
+	var err error
+	if field := s.GetrefTable().SoftDeleteField; field != nil && !s.unscoped {
+		result, err = s.softDelete(field)
+	} else {
+		s.clause.Set(clause.DELETE, s.tableName())
+		sql, vars := s.clause.Build(clause.DELETE, clause.WHERE)
+		result, err = s.Raw(sql, vars...).Exec()
+	}
 	if err != nil {
 		return 0, err
 	}
+	if err := s.CallMethod(AfterDelete, nil); err != nil {
+		return 0, err
+	}
 	return result.RowsAffected()
 }
 
-// Count COUNT操作外部接口
-func (s *Session) Count() (int64, error) {
-	s.clause.Set(clause.COUNT, s.GetrefTable().Name)
+// Count COUNT操作外部接口，传入列名时统计该列非 NULL 值的个数，设置了 Distinct 时只统计不重复的值
+func (s *Session) Count(column ...string) (int64, error) {
+	if err := s.modelErr(); err != nil {
+		return 0, err
+	}
+	s.softDeleteScope()
+	if len(column) > 0 {
+		s.clause.Set(clause.COUNT, s.tableName(), column[0], s.distinct)
+	} else {
+		s.clause.Set(clause.COUNT, s.tableName())
+	}
 	sql, vars := s.clause.Build(clause.COUNT, clause.WHERE)
 	row := s.Raw(sql, vars...).QueryRow()
 	var tmp int64
@@ -100,19 +281,111 @@ func (s *Session) Limit(num int) *Session {
 	return s
 }
 
-// Where 设置WHERE语句
-func (s *Session) Where(desc string, args ...interface{}) *Session {
-	var vars []interface{}
-	s.clause.Set(clause.WHERE, append(append(vars, desc), args...)...)
+// Where 追加WHERE条件，多次调用的条件之间使用 AND 连接
+// query 可以是带 ? 占位符的SQL片段及其参数，也可以是 clause.Eq 等构造的 clause.Expression
+func (s *Session) Where(query interface{}, args ...interface{}) *Session {
+	return s.setWhere(clause.And(s.where, condition(query, args...)))
+}
+
+// Or 将已有条件与新条件使用 OR 连接
+func (s *Session) Or(query interface{}, args ...interface{}) *Session {
+	return s.setWhere(clause.Or(s.where, condition(query, args...)))
+}
+
+// Not 追加一个取反的条件，与已有条件使用 AND 连接
+func (s *Session) Not(query interface{}, args ...interface{}) *Session {
+	return s.setWhere(clause.And(s.where, clause.Not(condition(query, args...))))
+}
+
+// setWhere 更新累积的条件并同步到WHERE子句
+func (s *Session) setWhere(where clause.Expression) *Session {
+	s.where = where
+	if where != nil {
+		s.clause.Set(clause.WHERE, where)
+	}
 	return s
 }
 
+// condition 将Where等方法的参数转换为表达式
+func condition(query interface{}, args ...interface{}) clause.Expression {
+	if expr, ok := query.(clause.Expression); ok {
+		return expr
+	}
+	return clause.Expr(query.(string), args...)
+}
+
 // Orderby 设置Order By语句
 func (s *Session) Orderby(desc string) *Session {
 	s.clause.Set(clause.ORDERBY, desc)
 	return s 
 }
 
+// Select 指定查询的列，列也可以是 count(*) AS total 形式的表达式，结果按列名写入 Find 的目标
+func (s *Session) Select(columns ...string) *Session {
+	s.selects = columns
+	return s
+}
+
+// Distinct 查询时去除重复的行
+func (s *Session) Distinct() *Session {
+	s.distinct = true
+	return s
+}
+
+// Offset 设置OFFSET语句，跳过前 num 行
+func (s *Session) Offset(num int) *Session {
+	s.clause.Set(clause.OFFSET, num)
+	return s
+}
+
+// GroupBy 设置GROUP BY语句
+func (s *Session) GroupBy(columns ...string) *Session {
+	s.clause.Set(clause.GROUPBY, columns)
+	return s
+}
+
+// Having 追加分组的过滤条件，参数形式与 Where 相同，多次调用的条件之间使用 AND 连接
+func (s *Session) Having(query interface{}, args ...interface{}) *Session {
+	s.having = clause.And(s.having, condition(query, args...))
+	s.clause.Set(clause.HAVING, s.having)
+	return s
+}
+
+// buildQuery 按照查询语句的子句顺序构造SQL语句，只设置了 OFFSET 时补充不限制行数的 LIMIT
+func (s *Session) buildQuery() (string, []interface{}) {
+	if s.clause.Has(clause.OFFSET) && !s.clause.Has(clause.LIMIT) {
+		s.clause.Set(clause.LIMIT, int64(math.MaxInt64))
+	}
+	return s.clause.Build(clause.SELECT, clause.WHERE, clause.GROUPBY, clause.HAVING, clause.ORDERBY, clause.LIMIT, clause.OFFSET)
+}
+
+// selectColumns 返回查询的列，未通过 Select 指定时为模型的全部列，没有模型时为 *
+// Select 指定的普通列名必须是模型的字段，count(*) AS total 等表达式不做检查
+func (s *Session) selectColumns() ([]string, error) {
+	switch {
+	case len(s.selects) > 0 && s.refTable != nil:
+		for _, column := range s.selects {
+			if isExpression(column) {
+				continue
+			}
+			if lookUpColumn(s.refTable, column[strings.LastIndex(column, ".")+1:]) == nil {
+				return nil, fmt.Errorf("column %s is not a field of %s", column, s.refTable.Name)
+			}
+		}
+		return s.selects, nil
+	case len(s.selects) > 0:
+		return s.selects, nil
+	case s.refTable != nil:
+		return s.refTable.FieldNames, nil
+	}
+	return []string{"*"}, nil
+}
+
+// isExpression 判断 Select 的参数是否为表达式而不是普通的列名
+func isExpression(column string) bool {
+	return strings.ContainsAny(column, " ()*'\"`")
+}
+
 // First 仅查找符合条件的第一个元素
 func (s *Session) First(value interface{}) error {
 	dest := reflect.Indirect(reflect.ValueOf(value))
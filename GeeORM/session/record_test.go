@@ -1,6 +1,9 @@
 package session
 
-import "testing"
+import (
+	"geeorm/clause"
+	"testing"
+)
 
 var (
 	user1 = &User{"Tom", 18}
@@ -66,3 +69,14 @@ func TestSession_DeleteAndCount(t *testing.T) {
 		t.Fatal("failed to delete or count")
 	}
 }
+
+func TestSession_WhereChain(t *testing.T) {
+	s := testRecordInit(t)
+	_, _ = s.Insert(user3, user4)
+	var users []User
+	err := s.Where(clause.Gt("Age", 15)).Where("Age < ?", 25).Or(clause.Eq("Name", "Sam")).
+		Not(clause.Like("Name", "J%")).Orderby("Age").Find(&users)
+	if err != nil || len(users) != 2 || users[0].Name != "Sam" || users[1].Name != "Tom" {
+		t.Fatal("failed to query with chained conditions, got", users)
+	}
+}
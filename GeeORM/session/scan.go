@@ -0,0 +1,142 @@
+package session
+
+import (
+	"database/sql"
+	"errors"
+	"geeorm/schema"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Scan 执行 Raw 设置的查询语句，将结果写入 dest
+// dest 为结构体、map[string]interface{} 或标量的指针时写入第一行，没有结果时返回 sql.ErrNoRows
+// dest 为它们切片的指针时追加全部的行；结构体按列名匹配字段，结果中无法匹配的列被忽略
+func (s *Session) Scan(dest interface{}) error {
+	rows, err := s.QueryRows()
+	if err != nil {
+		return err
+	}
+	if err := s.scanRows(rows, dest); err != nil {
+		_ = rows.Close()
+		return err
+	}
+	return rows.Close()
+}
+
+// scanRows 将查询结果写入 dest，规则与 Scan 相同
+func (s *Session) scanRows(rows *sql.Rows, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("scan destination must be a non-nil pointer")
+	}
+	v = v.Elem()
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			return sql.ErrNoRows
+		}
+		return s.newRowScanner(v.Type(), columns).scan(rows, v)
+	}
+	elemType, isPtr := v.Type().Elem(), false
+	if elemType.Kind() == reflect.Ptr {
+		elemType, isPtr = elemType.Elem(), true
+	}
+	scanner := s.newRowScanner(elemType, columns)
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		if err := scanner.scan(rows, elem.Elem()); err != nil {
+			return err
+		}
+		if isPtr {
+			v.Set(reflect.Append(v, elem))
+		} else {
+			v.Set(reflect.Append(v, elem.Elem()))
+		}
+	}
+	return rows.Err()
+}
+
+// rowScanner 将一行结果写入结构体、map 或标量，结构体的字段与列的对应关系只计算一次
+type rowScanner struct {
+	columns []string
+	fields  []*schema.Field // 每一列对应的结构体字段，无法匹配时为 nil
+}
+
+// newRowScanner 为类型 typ 的目标创建 rowScanner
+// 结构体只按列名匹配字段，不需要能够映射为表
+func (s *Session) newRowScanner(typ reflect.Type, columns []string) *rowScanner {
+	scanner := &rowScanner{columns: columns}
+	if isStruct(typ) {
+		scanner.fields = fieldsByColumn(schema.ScanFields(typ, s.naming), columns)
+	}
+	return scanner
+}
+
+// scan 将当前行写入 dest
+func (r *rowScanner) scan(rows *sql.Rows, dest reflect.Value) error {
+	values := make([]interface{}, len(r.columns))
+	switch {
+	case dest.Kind() == reflect.Map:
+		for i := range values {
+			values[i] = new(interface{})
+		}
+		if err := rows.Scan(values...); err != nil {
+			return err
+		}
+		if dest.IsNil() {
+			dest.Set(reflect.MakeMap(dest.Type()))
+		}
+		for i, column := range r.columns {
+			dest.SetMapIndex(reflect.ValueOf(column), reflect.ValueOf(values[i]).Elem())
+		}
+		return nil
+	case r.fields != nil:
+		for i, field := range r.fields {
+			if field == nil {
+				values[i] = new(interface{})
+				continue
+			}
+			values[i] = field.ScanTarget(dest.FieldByName(field.GoName))
+		}
+	default:
+		// 标量只接收第一列
+		for i := range values {
+			values[i] = new(interface{})
+		}
+		values[0] = dest.Addr().Interface()
+	}
+	return rows.Scan(values...)
+}
+
+// fieldsByColumn 返回每一列对应的字段，列名优先精确匹配，其次忽略大小写匹配，无法匹配时为 nil
+func fieldsByColumn(candidates []*schema.Field, columns []string) []*schema.Field {
+	fields := make([]*schema.Field, len(columns))
+	for i, column := range columns {
+		for _, field := range candidates {
+			if field.Name == column {
+				fields[i] = field
+				break
+			}
+			if fields[i] == nil && strings.EqualFold(field.Name, column) {
+				fields[i] = field
+			}
+		}
+	}
+	return fields
+}
+
+// isStruct 判断类型是否为按字段写入的结构体，time.Time 及实现了 sql.Scanner 的结构体视为标量
+func isStruct(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct || typ == reflect.TypeOf(time.Time{}) {
+		return false
+	}
+	_, ok := reflect.New(typ).Interface().(sql.Scanner)
+	return !ok
+}
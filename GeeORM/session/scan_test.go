@@ -0,0 +1,78 @@
+package session
+
+import "testing"
+
+type RegionTotal struct {
+	Region string
+	Total  float64
+}
+
+func TestSession_FindInto(t *testing.T) {
+	s := prepareSales(t)
+	var totals []RegionTotal
+	err := s.Model(&Sale{}).Select("Region", "sum(Amount) AS total").GroupBy("Region").Orderby("Region").Find(&totals)
+	if err != nil || len(totals) != 3 || totals[0].Region != "east" || totals[0].Total != 30 {
+		t.Fatal("failed to find into dto", totals, err)
+	}
+	var rows []map[string]interface{}
+	if err := s.Model(&Sale{}).Where("Region = ?", "west").Orderby("ID").Find(&rows); err != nil || len(rows) != 2 {
+		t.Fatal("failed to find into maps", rows, err)
+	}
+	if rows[0]["ID"] != int64(3) || rows[1]["Amount"] != float64(15) {
+		t.Fatal("unexpected map values", rows)
+	}
+	var sales []Sale
+	if err := s.Select("ID", "Unknown").Orderby("ID").Find(&sales); err == nil {
+		t.Fatal("expect error for unknown column", sales)
+	}
+	var dtos []RegionTotal
+	if err := s.Model(&Sale{}).Where("Region = ?", "east").Find(&dtos); err != nil || len(dtos) != 2 || dtos[0].Region != "east" {
+		t.Fatal("failed to find into dto without Select", dtos, err)
+	}
+	if table := s.GetrefTable(); table.Name != "Sale" {
+		t.Fatal("find into dto should not change the model, got", table.Name)
+	}
+}
+
+func TestSession_Scan(t *testing.T) {
+	s := prepareSales(t)
+	var totals []*RegionTotal
+	err := s.Raw("SELECT Region, sum(Amount) AS TOTAL, count(*) AS n FROM Sale GROUP BY Region ORDER BY Region").Scan(&totals)
+	if err != nil || len(totals) != 3 || totals[2].Region != "west" || totals[2].Total != 20 {
+		t.Fatal("failed to scan into dto", totals, err)
+	}
+	var top RegionTotal
+	if err := s.Raw("SELECT Region, Amount AS total FROM Sale ORDER BY Amount DESC").Scan(&top); err != nil || top.Region != "north" {
+		t.Fatal("failed to scan first row", top, err)
+	}
+	var count int
+	if err := s.Raw("SELECT count(*) FROM Sale").Scan(&count); err != nil || count != 5 {
+		t.Fatal("failed to scan scalar", count, err)
+	}
+	var ids []int
+	if err := s.Raw("SELECT ID FROM Sale WHERE Amount > ? ORDER BY ID", 12).Scan(&ids); err != nil || len(ids) != 3 || ids[0] != 2 {
+		t.Fatal("failed to scan scalars", ids, err)
+	}
+	var row map[string]interface{}
+	if err := s.Raw("SELECT Region FROM Sale WHERE ID = ?", 5).Scan(&row); err != nil || row["Region"] != "north" {
+		t.Fatal("failed to scan map", row, err)
+	}
+	if err := s.Raw("SELECT ID FROM Sale WHERE ID = ?", 100).Scan(&count); err == nil {
+		t.Fatal("expect sql.ErrNoRows")
+	}
+}
+
+type LooseTotal struct {
+	Region string
+	Amount interface{} `geeorm:"column:total"`
+	Extra  map[string]int
+}
+
+func TestSession_ScanLooseDTO(t *testing.T) {
+	s := prepareSales(t)
+	var totals []LooseTotal
+	err := s.Model(&Sale{}).Select("Region", "sum(Amount) AS total").GroupBy("Region").Orderby("Region").Find(&totals)
+	if err != nil || len(totals) != 3 || totals[0].Region != "east" || totals[0].Amount != float64(30) {
+		t.Fatal("failed to find into dto with interface{} field", totals, err)
+	}
+}
@@ -0,0 +1,71 @@
+package session
+
+import (
+	"database/sql/driver"
+	"testing"
+)
+
+type Price struct {
+	Cents int64
+}
+
+func (p Price) Value() (driver.Value, error) {
+	return p.Cents, nil
+}
+
+func (p *Price) Scan(src interface{}) error {
+	p.Cents, _ = src.(int64)
+	return nil
+}
+
+type Shipping struct {
+	Carrier string
+	Days    int
+}
+
+type Product struct {
+	ID       int `geeorm:"primaryKey"`
+	Price    Price
+	Attrs    map[string]string `geeorm:"serializer:json"`
+	Shipping Shipping          `geeorm:"serializer:gob"`
+}
+
+func TestSession_CustomTypes(t *testing.T) {
+	s := NewSession().Model(&Product{})
+	_ = s.DropTable()
+	if err := s.CreateTable(); err != nil {
+		t.Fatal(err)
+	}
+	p := &Product{ID: 1, Price: Price{1999}, Attrs: map[string]string{"color": "red"}, Shipping: Shipping{"UPS", 3}}
+	if _, err := s.Insert(p); err != nil {
+		t.Fatal(err)
+	}
+	var raw string
+	if err := s.Raw("SELECT Attrs FROM Product").QueryRow().Scan(&raw); err != nil || raw != `{"color":"red"}` {
+		t.Fatal("expect attrs stored as json, but got", raw, err)
+	}
+
+	var got Product
+	if err := s.Get(&got, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got.Price.Cents != 1999 || got.Attrs["color"] != "red" || got.Shipping != p.Shipping {
+		t.Fatal("failed to decode custom types", got)
+	}
+
+	got.Attrs["size"] = "L"
+	got.Shipping.Days = 5
+	if _, err := s.Save(&got); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Where("ID = ?", 1).Update("Attrs", map[string]string{"color": "blue"}); err != nil {
+		t.Fatal(err)
+	}
+	var products []Product
+	if err := s.Find(&products); err != nil || len(products) != 1 {
+		t.Fatal("failed to query products", products, err)
+	}
+	if products[0].Attrs["color"] != "blue" || products[0].Attrs["size"] != "" || products[0].Shipping.Days != 5 {
+		t.Fatal("failed to update serialized fields", products[0])
+	}
+}
@@ -0,0 +1,28 @@
+package session
+
+import (
+	"database/sql"
+	"geeorm/clause"
+	"geeorm/schema"
+)
+
+// Unscoped 使本次链式调用忽略软删除：查询包含已软删除的行，Delete 执行物理删除
+func (s *Session) Unscoped() *Session {
+	s.unscoped = true
+	return s
+}
+
+// softDeleteScope 模型含有软删除标记列时，为查询条件追加 标记列 IS NULL 以排除已软删除的行
+func (s *Session) softDeleteScope() {
+	if field := s.GetrefTable().SoftDeleteField; field != nil && !s.unscoped {
+		s.setWhere(clause.And(s.where, clause.IsNull(field.Name)))
+	}
+}
+
+// softDelete 将删除转换为写入删除时间的更新
+func (s *Session) softDelete(field *schema.Field) (sql.Result, error) {
+	s.softDeleteScope()
+	s.clause.Set(clause.UPDATE, s.tableName(), map[string]interface{}{field.Name: s.now()})
+	sql, vars := s.clause.Build(clause.UPDATE, clause.WHERE)
+	return s.Raw(sql, vars...).Exec()
+}
@@ -0,0 +1,44 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+type Article struct {
+	ID        int `geeorm:"primaryKey"`
+	Title     string
+	DeletedAt *time.Time
+}
+
+func TestSession_SoftDelete(t *testing.T) {
+	s := NewSession().Model(&Article{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	_, _ = s.Insert(&Article{ID: 1, Title: "Go"}, &Article{ID: 2, Title: "ORM"})
+
+	if affected, err := s.Where("ID = ?", 1).Delete(); err != nil || affected != 1 {
+		t.Fatal("failed to soft delete", err)
+	}
+	if count, _ := s.Count(); count != 1 {
+		t.Fatal("expect soft deleted rows to be excluded, got", count)
+	}
+	if affected, _ := s.Where("ID = ?", 1).Update("Title", "Rust"); affected != 0 {
+		t.Fatal("expect soft deleted rows not to be updated")
+	}
+
+	var articles []Article
+	if err := s.Unscoped().Orderby("ID").Find(&articles); err != nil || len(articles) != 2 {
+		t.Fatal("failed to query unscoped rows", err)
+	}
+	if articles[0].DeletedAt == nil || articles[1].DeletedAt != nil {
+		t.Fatal("failed to record deleted time, got", articles)
+	}
+
+	if affected, _ := s.Unscoped().Where("ID = ?", 1).Delete(); affected != 1 {
+		t.Fatal("failed to hard delete")
+	}
+	if count, _ := s.Unscoped().Count(); count != 1 {
+		t.Fatal("expect hard deleted rows to be removed, got", count)
+	}
+}
@@ -5,18 +5,40 @@ import (
 	"geeorm/log"
 	"geeorm/schema"
 	"reflect"
-	"strings"
 )
 
 // Model 为会话创建或更新维护的表信息
+// 解析失败时保留原有的表信息，错误由 Err 返回，并由本次链式调用的操作返回
 func (sess *Session) Model(value interface{}) *Session{
 	// 当会话记录的表为nil时创建或者表类型发生变化时更新
 	if sess.refTable == nil || reflect.TypeOf(sess.refTable.Model) != reflect.TypeOf(value) {
-		sess.refTable = schema.Parse(value, sess.dial)
+		table, err := schema.ParseWithNaming(value, sess.dial, sess.naming)
+		if err != nil {
+			sess.err = err
+			return sess
+		}
+		sess.refTable = table
 	}
+	// 类型未变化时复用表信息，但记录最新传入的对象，供钩子与关联操作使用
+	sess.refTable.Model = value
+	sess.modelSet = true
 	return sess
 }
 
+// Err 返回本次链式调用中解析模型时发生的错误
+func (sess *Session) Err() error {
+	return sess.err
+}
+
+// modelErr 返回本次链式调用中解析模型时发生的错误，存在错误时清空链式调用的状态
+func (sess *Session) modelErr() error {
+	err := sess.err
+	if err != nil {
+		sess.Clear()
+	}
+	return err
+}
+
 // GetrefTable 返回当前会话维持的表信息
 func (sess *Session) GetrefTable() *schema.Schema {
 	if sess.refTable == nil {
@@ -25,29 +47,90 @@ func (sess *Session) GetrefTable() *schema.Schema {
 	return sess.refTable
 }
 
-// CreateTable 在数据库中创建一个新的表
+// Table 为本次链式调用指定表名，用于共用一个结构体的分表，例如 events_2026_10
+// 表名只覆盖本次操作，不会重新解析模型，执行后随 Clear 失效
+func (sess *Session) Table(name string) *Session {
+	sess.table = name
+	return sess
+}
+
+// tableName 返回本次操作的表名，未通过 Table 指定时使用模型对应的表名
+func (sess *Session) tableName() string {
+	if sess.table != "" {
+		return sess.table
+	}
+	return sess.GetrefTable().Name
+}
+
+// CreateTable 在数据库中创建一个新的表，并创建标签中声明的索引
 func (sess *Session) CreateTable() error {
+	if err := sess.modelErr(); err != nil {
+		return err
+	}
+	// 执行建表语句会清空链式调用的状态，先记录表名
+	table, name := sess.GetrefTable(), sess.tableName()
+	if _, err := sess.Raw(table.CreateTableSQL(sess.dial, name)).Exec(); err != nil {
+		return err
+	}
+	for _, idx := range table.Indexes {
+		if _, err := sess.Raw(idx.CreateSQL(sess.dial, name)).Exec(); err != nil {
+			return err
+		}
+	}
+	return sess.CreateJoinTables()
+}
+
+// CreateJoinTables 为当前表的多对多关联创建连接表，连接表已存在时跳过
+func (sess *Session) CreateJoinTables() error {
+	for _, rel := range sess.GetrefTable().Relationships {
+		if rel.Kind != schema.ManyToMany {
+			continue
+		}
+		sql, err := sess.JoinTableSQL(rel)
+		if err != nil {
+			return err
+		}
+		if _, err := sess.Raw(sql).Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// JoinTableSQL 生成当前表多对多关联 rel 的连接表建表语句，连接表以两侧的列组成联合主键
+func (sess *Session) JoinTableSQL(rel *schema.Relationship) (string, error) {
 	table := sess.GetrefTable()
-	var columns []string
-	for _, field := range table.Fields {
-		columns = append(columns, fmt.Sprintf("%s %s %s", field.Name, field.Type, field.Tag))
+	relTable, err := sess.parseType(rel.Type)
+	if err != nil {
+		return "", err
 	}
-	desc := strings.Join(columns, ",")
-	_, err := sess.Raw(fmt.Sprintf("CREATE TABLE %s (%s);", table.Name, desc)).Exec()
-	return err
+	owner, related := table.LookUpField(rel.ForeignKey), relTable.LookUpField(rel.References)
+	if owner == nil || related == nil {
+		return "", fmt.Errorf("invalid many2many relationship %s of %s", rel.Name, table.Name)
+	}
+	fk, ref := sess.dial.Quote(rel.JoinForeignKey), sess.dial.Quote(rel.JoinReferences)
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s %s, %s %s, PRIMARY KEY (%s, %s))",
+		sess.dial.Quote(rel.JoinTable), fk, owner.Type, ref, related.Type, fk, ref), nil
+}
+
+// parseType 解析给定结构体类型对应的表概要，不改变会话当前维护的表
+func (sess *Session) parseType(typ reflect.Type) (*schema.Schema, error) {
+	return schema.ParseWithNaming(reflect.New(typ).Interface(), sess.dial, sess.naming)
 }
 
 // DropTable 根据表名从数据库中删除一张表
 func (sess *Session) DropTable() error {
-	_, err := sess.Raw(fmt.Sprintf("DROP TABLE IF EXISTS %s", sess.GetrefTable().Name)).Exec()
+	if err := sess.modelErr(); err != nil {
+		return err
+	}
+	_, err := sess.Raw(fmt.Sprintf("DROP TABLE IF EXISTS %s", sess.dial.Quote(sess.tableName()))).Exec()
 	return err
 }
 
-// HasTable 检查数据库中是否存在当前会话中维持的数据表
+// HasTable 检查数据库中是否存在当前会话中维持的数据表，模型解析失败时返回 false
 func (sess *Session) HasTable() bool {
-	sql, values := sess.dial.TableExistSQL(sess.GetrefTable().Name)
-	row := sess.Raw(sql, values...).QueryRow()
-	var tmp string
-	_ = row.Scan(&tmp)
-	return tmp == sess.GetrefTable().Name
+	if sess.modelErr() != nil {
+		return false
+	}
+	return sess.TableExists(sess.tableName())
 }
\ No newline at end of file
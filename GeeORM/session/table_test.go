@@ -1,6 +1,10 @@
 package session
 
-import "testing"
+import (
+	"geeorm/schema"
+	"testing"
+	"time"
+)
 
 type User struct {
 	Name string `geeorm:"PRIMARY KEY"`
@@ -14,4 +18,129 @@ func TestModel(t *testing.T) {
 	if !sess.HasTable() {
 		t.Fatalf("Create table failed")
 	}
-}
\ No newline at end of file
+}
+
+type UserProfile struct {
+	UserID   int `geeorm:"primaryKey"`
+	NickName string
+}
+
+type LegacyProfile struct {
+	UserID int `geeorm:"primaryKey"`
+}
+
+func (LegacyProfile) TableName() string {
+	return "legacy_profile"
+}
+
+func TestSession_NamingStrategy(t *testing.T) {
+	naming := schema.NamingStrategy{TablePrefix: "t_", SnakeCase: true, PluralTables: true}
+	s := NewSession().SetNamingStrategy(naming).Model(&UserProfile{})
+	if table := s.GetrefTable(); table.Name != "t_user_profiles" || table.FieldNames[1] != "nick_name" {
+		t.Fatal("failed to apply naming strategy, got", table.Name, table.FieldNames)
+	}
+	_ = s.DropTable()
+	if err := s.CreateTable(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Insert(&UserProfile{UserID: 1, NickName: "Tom"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Where("user_id = ?", 1).Update("nick_name", "Sam"); err != nil {
+		t.Fatal(err)
+	}
+	var profiles []UserProfile
+	if err := s.Find(&profiles); err != nil || len(profiles) != 1 || profiles[0].NickName != "Sam" {
+		t.Fatal("failed to query with naming strategy", profiles, err)
+	}
+	if name := NewSession().SetNamingStrategy(naming).Model(&LegacyProfile{}).GetrefTable().Name; name != "legacy_profile" {
+		t.Fatal("expect TableName to override naming strategy, got", name)
+	}
+}
+
+type Event struct {
+	ID   int    `geeorm:"primaryKey"`
+	Kind string `geeorm:"index"`
+}
+
+func TestSession_Table(t *testing.T) {
+	s := NewSession().Model(&Event{})
+	for _, name := range []string{"events_2026_10", "events_2026_11"} {
+		_ = s.Table(name).DropTable()
+		if err := s.Table(name).CreateTable(); err != nil {
+			t.Fatal("failed to create partition", name, err)
+		}
+	}
+	if _, err := s.Table("events_2026_10").Insert(&Event{ID: 1, Kind: "login"}, &Event{ID: 2, Kind: "logout"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Table("events_2026_11").Insert(&Event{ID: 1, Kind: "login"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Table("events_2026_10").Where("ID = ?", 2).Update("Kind", "exit"); err != nil {
+		t.Fatal(err)
+	}
+	var events []Event
+	if err := s.Table("events_2026_10").Orderby("ID").Find(&events); err != nil || len(events) != 2 || events[1].Kind != "exit" {
+		t.Fatal("failed to query partition", events, err)
+	}
+	if n, err := s.Table("events_2026_11").Delete(); err != nil || n != 1 {
+		t.Fatal("failed to delete from partition", n, err)
+	}
+	if n, err := s.Table("events_2026_10").Count(); err != nil || n != 2 {
+		t.Fatal("expect 2, but got", n, err)
+	}
+	if s.HasTable() || s.GetrefTable().Name != "Event" {
+		t.Fatal("table override should not leak into later calls")
+	}
+}
+
+type InvalidModel struct {
+	ID int `geeorm:"primaryKey;unknown"`
+}
+
+func TestSession_ModelError(t *testing.T) {
+	s := NewSession().Model(&User{})
+	if err := s.Model(&InvalidModel{}).CreateTable(); err == nil {
+		t.Fatal("expect error for invalid tag")
+	}
+	if _, err := s.Insert(&InvalidModel{ID: 1}); err == nil {
+		t.Fatal("expect error for invalid tag")
+	}
+	var models []InvalidModel
+	if err := s.Find(&models); err == nil {
+		t.Fatal("expect error for invalid tag")
+	}
+	if s.Err() != nil || s.GetrefTable().Name != "User" {
+		t.Fatal("model error should not leak into later calls")
+	}
+}
+
+type Note struct {
+	ID        int `geeorm:"primaryKey"`
+	Body      string
+	Total     int
+	DeletedAt *time.Time
+}
+
+func TestSession_TableFind(t *testing.T) {
+	prepare := NewSession().Model(&Note{})
+	_ = prepare.Table("notes_1").DropTable()
+	if err := prepare.Table("notes_1").CreateTable(); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = prepare.Table("notes_1").Insert(&Note{ID: 1, Body: "a", Total: 10}, &Note{ID: 2, Body: "b", Total: 20})
+	if _, err := prepare.Table("notes_1").Where("ID = ?", 2).Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 全新的会话与使用过其他模型的会话，Table 都只覆盖表名
+	used := NewSession().Model(&User{})
+	_ = used.HasTable()
+	for _, s := range []*Session{NewSession(), used} {
+		var notes []Note
+		if err := s.Table("notes_1").Find(&notes); err != nil || len(notes) != 1 || notes[0].Total != 10 {
+			t.Fatal("failed to find from table with element model", notes, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package session
+
+import (
+	"geeorm/schema"
+	"reflect"
+	"time"
+)
+
+// SetNowFunc 设置会话获取当前时间的函数，自动时间戳与软删除均使用它，便于在测试中固定时间
+func (s *Session) SetNowFunc(f func() time.Time) *Session {
+	s.nowFunc = f
+	return s
+}
+
+// now 返回会话的当前时间
+func (s *Session) now() time.Time {
+	if s.nowFunc != nil {
+		return s.nowFunc()
+	}
+	return time.Now()
+}
+
+// timestampValue 根据成员变量类型转换时间，time.Time 原样写入，整数按精度写入 Unix 时间戳
+func timestampValue(typ reflect.Type, unit time.Duration, now time.Time) reflect.Value {
+	switch typ {
+	case reflect.TypeOf(time.Time{}):
+		return reflect.ValueOf(now)
+	case reflect.TypeOf(&time.Time{}):
+		return reflect.ValueOf(&now)
+	}
+	return reflect.ValueOf(now.UnixNano() / int64(unit)).Convert(typ)
+}
+
+// setCreateTimestamps 为待插入的对象填充尚未设置的创建及更新时间，对象需要以指针传入
+func (s *Session) setCreateTimestamps(table *schema.Schema, value interface{}) {
+	dest := reflect.Indirect(reflect.ValueOf(value))
+	if !dest.CanSet() {
+		return
+	}
+	now := s.now()
+	for _, field := range table.Fields {
+		if !field.AutoCreateTime && !field.AutoUpdateTime {
+			continue
+		}
+		if f := dest.FieldByName(field.GoName); f.IsZero() {
+			f.Set(timestampValue(f.Type(), field.TimeUnit, now))
+		}
+	}
+}
+
+// setUpdateTimestamps 为UPDATE语句追加更新时间列，已显式设置的列不会被覆盖
+func (s *Session) setUpdateTimestamps(table *schema.Schema, m map[string]interface{}) {
+	modelType := reflect.Indirect(reflect.ValueOf(table.Model)).Type()
+	now := s.now()
+	for _, field := range table.Fields {
+		if _, ok := m[field.Name]; ok || !field.AutoUpdateTime {
+			continue
+		}
+		sf, _ := modelType.FieldByName(field.GoName)
+		m[field.Name] = timestampValue(sf.Type, field.TimeUnit, now).Interface()
+	}
+}
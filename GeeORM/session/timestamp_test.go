@@ -0,0 +1,34 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+type Post struct {
+	ID        int `geeorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt int64
+	SyncedAt  int64 `geeorm:"autoUpdateTime:milli"`
+}
+
+func TestSession_Timestamps(t *testing.T) {
+	now := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	s := NewSession().SetNowFunc(func() time.Time { return now }).Model(&Post{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	post := &Post{ID: 1}
+	if _, err := s.Insert(post); err != nil {
+		t.Fatal("failed to insert", err)
+	}
+	if !post.CreatedAt.Equal(now) || post.UpdatedAt != now.Unix() || post.SyncedAt != now.UnixNano()/int64(time.Millisecond) {
+		t.Fatal("failed to fill timestamps on insert, got", post)
+	}
+
+	now = now.Add(time.Hour)
+	_, _ = s.Where("ID = ?", 1).Update("ID", 1)
+	p := &Post{}
+	if err := s.First(p); err != nil || !p.CreatedAt.Equal(post.CreatedAt) || p.UpdatedAt != now.Unix() {
+		t.Fatal("failed to fill timestamps on update, got", p, err)
+	}
+}
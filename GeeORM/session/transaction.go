@@ -1,33 +1,99 @@
 package session
 
-import "geeorm/log"
+import (
+	"fmt"
+	"geeorm/log"
+)
 
-// Begin 开始事务
+// TxFunc 事务函数模板
+type TxFunc func(*Session) (result interface{}, err error)
+
+// savepointName 根据嵌套深度生成保存点名称
+func savepointName(depth int) string {
+	return fmt.Sprintf("geeorm_sp_%d", depth)
+}
+
+// Begin 开始事务，会话已处于事务中时创建保存点实现嵌套事务
 func (s *Session) Begin() (err error) {
+	if s.tx != nil {
+		s.savepoints++
+		name := savepointName(s.savepoints)
+		log.Info("transcation savepoint", name)
+		if _, err = s.tx.ExecContext(s.Context(), s.dial.SavepointSQL(name)); err != nil {
+			s.savepoints--
+			log.Error(err)
+		}
+		return
+	}
 	log.Info("transcation begin")
-	if s.tx, err = s.db.Begin(); err != nil {
+	if s.tx, err = s.db.BeginTx(s.Context(), nil); err != nil {
 		log.Error(err)
 		return
 	}
 	return
 }
 
-// Commit 事务提交
+// Commit 事务提交，嵌套事务中释放当前保存点
 func (s *Session) Commit() (err error) {
+	if s.savepoints > 0 {
+		name := savepointName(s.savepoints)
+		s.savepoints--
+		log.Info("transcation release", name)
+		if _, err = s.tx.ExecContext(s.Context(), s.dial.ReleaseSavepointSQL(name)); err != nil {
+			log.Error(err)
+		}
+		return
+	}
 	log.Info("transcation commit")
-	if err = s.tx.Commit(); err != nil {
+	err = s.tx.Commit()
+	s.tx = nil
+	if err != nil {
 		log.Error(err)
 		return
 	}
 	return
 }
 
-// Rollback 事务提交
+// Rollback 事务回滚，嵌套事务中回滚到当前保存点并释放该保存点
 func (s *Session) Rollback() (err error) {
+	if s.savepoints > 0 {
+		name := savepointName(s.savepoints)
+		s.savepoints--
+		log.Info("transcation rollback to", name)
+		if _, err = s.tx.ExecContext(s.Context(), s.dial.RollbackToSavepointSQL(name)); err != nil {
+			log.Error(err)
+			return
+		}
+		if _, err = s.tx.ExecContext(s.Context(), s.dial.ReleaseSavepointSQL(name)); err != nil {
+			log.Error(err)
+		}
+		return
+	}
 	log.Info("transcation rollback")
-	if err = s.tx.Rollback(); err != nil {
+	err = s.tx.Rollback()
+	s.tx = nil
+	if err != nil {
 		log.Error(err)
 		return
 	}
 	return
-}
\ No newline at end of file
+}
+
+// Transaction 在会话上执行事务函数，函数返回错误或panic时回滚，否则提交
+// 会话已处于事务中时以保存点实现嵌套，因此库代码无需关心调用方的事务状态
+func (s *Session) Transaction(f TxFunc) (result interface{}, err error) {
+	if err = s.Begin(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = s.Rollback()
+			panic(p)
+		} else if err != nil {
+			_ = s.Rollback()
+		} else {
+			err = s.Commit()
+		}
+	}()
+	return f(s)
+}
@@ -0,0 +1,35 @@
+package session
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSession_NestedTransaction(t *testing.T) {
+	s := NewSession().Model(&User{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	_, err := s.Transaction(func(s *Session) (result interface{}, err error) {
+		if _, err = s.Insert(user1); err != nil {
+			return
+		}
+		_, inner := s.Transaction(func(s *Session) (result interface{}, err error) {
+			_, _ = s.Insert(user2)
+			return nil, errors.New("Error")
+		})
+		if inner == nil {
+			t.Fatal("expect inner transaction to fail")
+		}
+		if _, err := s.Raw(s.dial.RollbackToSavepointSQL(savepointName(1))).Exec(); err == nil {
+			t.Fatal("expect savepoint to be released after rollback")
+		}
+		return s.Insert(user3)
+	})
+	var users []User
+	if err != nil || s.Find(&users) != nil || len(users) != 2 {
+		t.Fatal("failed to rollback to savepoint, got", users, err)
+	}
+	if users[0].Name != "Tom" || users[1].Name != "Liang" {
+		t.Fatal("failed to rollback to savepoint, got", users)
+	}
+}
@@ -0,0 +1,89 @@
+package session
+
+import (
+	"errors"
+	"geeorm/clause"
+	"geeorm/schema"
+	"reflect"
+)
+
+// ErrStaleObject 乐观锁校验失败，记录已被其他操作修改或删除，调用方可以重新读取后重试
+var ErrStaleObject = errors.New("geeorm: stale object, the record has been modified or deleted")
+
+// versionScope 模型含有版本号列时，将版本号更新为自增表达式
+// 更新的列中包含版本号时将其作为期望的当前版本号追加到查询条件中，并返回 true 表示需要校验影响行数
+func (s *Session) versionScope(table *schema.Schema, m map[string]interface{}) bool {
+	field := table.VersionField
+	if field == nil {
+		return false
+	}
+	expected, checked := m[field.Name]
+	if checked {
+		s.Where(clause.Eq(field.Name, expected))
+	}
+	m[field.Name] = clause.Expr(s.dial.Quote(field.Name) + " + 1")
+	return checked
+}
+
+// primaryKeyCondition 根据对象的主键字段值构造查询条件，支持联合主键
+func primaryKeyCondition(table *schema.Schema, dest reflect.Value) (clause.Expression, error) {
+	if len(table.PrimaryFields) == 0 {
+		return nil, errors.New("primary key of " + table.Name + " is not declared")
+	}
+	var conds []clause.Expression
+	for _, field := range table.PrimaryFields {
+		conds = append(conds, clause.Eq(field.Name, dest.FieldByName(field.GoName).Interface()))
+	}
+	return clause.And(conds...), nil
+}
+
+// updateModel 根据主键使用对象的字段值更新记录，更新成功后同步对象中的版本号与更新时间
+func (s *Session) updateModel(value interface{}) (int64, error) {
+	if err := s.Model(value).modelErr(); err != nil {
+		return 0, err
+	}
+	table := s.GetrefTable()
+	dest := reflect.Indirect(reflect.ValueOf(value))
+	cond, err := primaryKeyCondition(table, dest)
+	if err != nil {
+		s.Clear()
+		return 0, err
+	}
+	m := make(map[string]interface{})
+	now := s.now()
+	for _, field := range table.Fields {
+		f := dest.FieldByName(field.GoName)
+		switch {
+		case field.PrimaryKey || field.SoftDelete || field.AutoCreateTime && !field.AutoUpdateTime:
+			continue
+		case field.AutoUpdateTime:
+			m[field.Name] = timestampValue(f.Type(), field.TimeUnit, now).Interface()
+		default:
+			m[field.Name] = f.Interface()
+		}
+	}
+	affected, err := s.Where(cond).Update(m)
+	if err != nil || !dest.CanSet() {
+		return affected, err
+	}
+	for _, field := range table.Fields {
+		f := dest.FieldByName(field.GoName)
+		switch {
+		case field.Version:
+			increase(f)
+		case field.AutoUpdateTime:
+			f.Set(reflect.ValueOf(m[field.Name]))
+		}
+	}
+	return affected, nil
+}
+
+// increase 将整数类型的版本号加一
+func increase(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(v.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(v.Uint() + 1)
+	}
+}
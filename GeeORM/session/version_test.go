@@ -0,0 +1,43 @@
+package session
+
+import "testing"
+
+type Wallet struct {
+	ID      int `geeorm:"primaryKey"`
+	Balance int
+	Version int `geeorm:"version"`
+}
+
+func TestSession_OptimisticLock(t *testing.T) {
+	s := NewSession().Model(&Wallet{})
+	_ = s.DropTable()
+	_ = s.CreateTable()
+	_, _ = s.Insert(&Wallet{ID: 1, Balance: 100})
+
+	w1, w2 := &Wallet{}, &Wallet{}
+	_ = s.First(w1)
+	_ = s.First(w2)
+	w1.Balance -= 30
+	if affected, err := s.Update(w1); err != nil || affected != 1 || w1.Version != 1 {
+		t.Fatal("failed to update with version, got", affected, err, w1)
+	}
+	w2.Balance -= 50
+	if _, err := s.Update(w2); err != ErrStaleObject {
+		t.Fatal("expect ErrStaleObject, but got", err)
+	}
+
+	_ = s.First(w2)
+	if w2.Balance != 70 || w2.Version != 1 {
+		t.Fatal("expect stale update to be rejected, got", w2)
+	}
+	if _, err := s.Where("ID = ?", 1).Update("Balance", 20, "Version", 0); err != ErrStaleObject {
+		t.Fatal("expect ErrStaleObject, but got", err)
+	}
+	if affected, err := s.Where("ID = ?", 1).Update("Balance", 20); err != nil || affected != 1 {
+		t.Fatal("failed to update without version check", err)
+	}
+	_ = s.First(w2)
+	if w2.Version != 2 {
+		t.Fatal("expect version to be increased, got", w2)
+	}
+}